@@ -551,7 +551,13 @@ func issuerCreateAndStoreCredentialDef(wallet *wallet.Wallet, issuerDID, schemaJ
 		}
 	}
 
-	err := indy.IssuerCreateAndStoreCredentialDef(wallet.Handle(), issuerDID, schemaJSON, tag, signatureType, configJSON, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.IssuerCreateAndStoreCredentialDef(walletHandle, issuerDID, schemaJSON, tag, signatureType, configJSON, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -579,7 +585,13 @@ func issuerCreateCredentialOffer(wallet *wallet.Wallet, credDefID string) (chan
 		}
 	}
 
-	err := indy.IssuerCreateCredentialOffer(wallet.Handle(), credDefID, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.IssuerCreateCredentialOffer(walletHandle, credDefID, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -626,7 +638,13 @@ func issuerCreateCredential(wallet *wallet.Wallet, credOfferJSON, credReqJSON, c
 		}
 	}
 
-	err := indy.IssuerCreateCredential(wallet.Handle(), credOfferJSON, credReqJSON, credValuesJSON, revRegID, blobStorageReaderHandle, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.IssuerCreateCredential(walletHandle, credOfferJSON, credReqJSON, credValuesJSON, revRegID, blobStorageReaderHandle, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -649,7 +667,13 @@ func proverCreateMasterSecret(wallet *wallet.Wallet, masterSecretID string) (cha
 		}
 	}
 
-	err := indy.ProverCreateMasterSecret(wallet.Handle(), masterSecretID, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.ProverCreateMasterSecret(walletHandle, masterSecretID, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -698,7 +722,13 @@ func proverCreateCredentialReq(wallet *wallet.Wallet, proverDID, credentialOffer
 		}
 	}
 
-	err := indy.ProverCreateCredentialReq(wallet.Handle(), proverDID, credentialOfferJSON, credentialDefJSON, masterSecretID, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.ProverCreateCredentialReq(walletHandle, proverDID, credentialOfferJSON, credentialDefJSON, masterSecretID, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -734,7 +764,13 @@ func proverStoreCredential(wallet *wallet.Wallet, credID, credReqMetadataJSON, c
 		}
 	}
 
-	err := indy.ProverStoreCredential(wallet.Handle(), credID, credReqMetadataJSON, credJSON, credDefJSON, revRegDefJSON, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.ProverStoreCredential(walletHandle, credID, credReqMetadataJSON, credJSON, credDefJSON, revRegDefJSON, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -762,7 +798,13 @@ func proverGetCredentialsForProofReq(wallet *wallet.Wallet, proofRequest string)
 		}
 	}
 
-	err := indy.ProverGetCredentialsForProofReq(wallet.Handle(), proofRequest, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.ProverGetCredentialsForProofReq(walletHandle, proofRequest, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -810,7 +852,13 @@ func proverCreateProof(wallet *wallet.Wallet, proofRequest, requestedCredentials
 		}
 	}
 
-	err := indy.ProverCreateProof(wallet.Handle(), proofRequest, requestedCredentials, masterSecret, schemas, credentialDefs, revStates, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.ProverCreateProof(walletHandle, proofRequest, requestedCredentials, masterSecret, schemas, credentialDefs, revStates, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
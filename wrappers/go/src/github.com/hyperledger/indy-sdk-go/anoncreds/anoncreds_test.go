@@ -8,6 +8,9 @@ package anoncreds
 
 import (
 	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/wallet"
 )
 
 const (
@@ -23,3 +26,25 @@ func TestIssuerCreateSchema(t *testing.T) {
 	}
 	t.Logf("Created schema - ID [%s], JSON [%s]", schemaID, schemaJSON)
 }
+
+func TestClosedWallet(t *testing.T) {
+	w, err := getWallet("closed_wallet", "pool1")
+	if err != nil {
+		t.Fatalf("Error received from getWallet: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+
+	if _, err := ProverCreateMasterSecret(w, ""); err != wallet.ErrClosed {
+		t.Fatalf("Expecting error [%s] from ProverCreateMasterSecret but got [%v]", wallet.ErrClosed, err)
+	}
+}
+
+func getWallet(walletName, poolName string) (*wallet.Wallet, error) {
+	err := wallet.Create(poolName, walletName, "", "", "")
+	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		return nil, err
+	}
+	return wallet.Open(walletName, "", "")
+}
@@ -0,0 +1,292 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/crypto"
+	"github.com/hyperledger/indy-sdk-go/did"
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/ledger"
+	"github.com/hyperledger/indy-sdk-go/pairwise"
+	"github.com/hyperledger/indy-sdk-go/pool"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+// nonceBits is the size of the random nonce of a connection request
+const nonceBits = 128
+
+// The onboarding protocol establishes a pairwise connection between an inviter and an invitee:
+//
+//   1. The inviter creates a DID for the connection, optionally writes it to the ledger,
+//      and sends a connection request with the DID and a nonce to the invitee.
+//   2. The invitee creates a DID for the connection and sends back a connection response
+//      with its DID, verkey and the nonce, anoncrypted for the verkey of the inviter's DID.
+//   3. The inviter decrypts the response, authenticates the invitee by comparing the nonce
+//      and optionally writes the invitee's DID to the ledger.
+//
+// Both parties store the other's DID and the pairwise relationship in their wallets.
+
+// Request is the connection request sent by the inviter
+type Request struct {
+	// DID is the inviter's DID for the connection
+	DID string `json:"did"`
+
+	// VerKey is the verkey of the inviter's DID. If empty the invitee reads it from the ledger.
+	VerKey string `json:"verkey,omitempty"`
+
+	// Nonce is the nonce that the invitee returns in the response
+	Nonce string `json:"nonce"`
+}
+
+// Response is the connection response sent by the invitee. It's sent anoncrypted.
+type Response struct {
+	// DID is the invitee's DID for the connection
+	DID string `json:"did"`
+
+	// VerKey is the verkey of the invitee's DID
+	VerKey string `json:"verkey"`
+
+	// Nonce is the nonce of the request
+	Nonce string `json:"nonce"`
+}
+
+// Connection is an established connection
+type Connection struct {
+	// MyDID is my DID for the connection
+	MyDID string
+
+	// MyVerKey is the verkey of my DID
+	MyVerKey string
+
+	// TheirDID is the other party's DID for the connection
+	TheirDID string
+
+	// TheirVerKey is the verkey of the other party's DID
+	TheirVerKey string
+}
+
+// Inviter is the party that starts the onboarding
+type Inviter struct {
+	wallet       *wallet.Wallet
+	pool         *pool.Pool
+	submitterDID string
+}
+
+// NewInviter returns an inviter that stores the connections in the given wallet. If the pool
+// is not nil, the DIDs of both parties are written to the ledger with NYMs submitted by the
+// submitter DID, which must be a Steward or Trust Anchor in the wallet.
+func NewInviter(wallet *wallet.Wallet, pool *pool.Pool, submitterDID string) *Inviter {
+	return &Inviter{wallet: wallet, pool: pool, submitterDID: submitterDID}
+}
+
+// CreateRequest creates a DID for a new connection and returns the connection request
+// that is sent to the invitee. The request must be kept to accept the response.
+func (i *Inviter) CreateRequest() (*Request, error) {
+	if i.pool != nil && i.submitterDID == "" {
+		return nil, fmt.Errorf("submitter DID must be specified to write to the ledger")
+	}
+
+	nonce, err := NewNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	myDID, err := did.CreateAndStoreMyDIDWithOptions(i.wallet, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating DID for connection: %s", err)
+	}
+
+	if i.pool != nil {
+		if err := sendNYM(i.pool, i.wallet, i.submitterDID, myDID); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debugf("Created connection request for DID [%s] - Wallet [%s]", myDID.DID, i.wallet.Name)
+	return &Request{DID: myDID.DID, VerKey: myDID.VerKey, Nonce: nonce}, nil
+}
+
+// AcceptResponse decrypts the anoncrypted connection response to the given request and
+// authenticates the invitee by the nonce. The invitee's DID and the pairwise relationship
+// are stored in the wallet and, if the inviter has a pool, the invitee's DID is written to
+// the ledger.
+func (i *Inviter) AcceptResponse(request *Request, encryptedResponse []byte) (*Connection, error) {
+	if request == nil {
+		return nil, fmt.Errorf("connection request must be specified")
+	}
+
+	myVerKey, err := did.KeyForLocalDID(i.wallet, request.DID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting key of DID [%s]: %s", request.DID, err)
+	}
+
+	responseJSON, err := crypto.AnonDecrypt(i.wallet, myVerKey, encryptedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting connection response: %s", err)
+	}
+	response, err := parseResponse(responseJSON)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(response.Nonce), []byte(request.Nonce)) != 1 {
+		return nil, fmt.Errorf("nonce of connection response doesn't match the request")
+	}
+
+	theirDID := &did.TheirDIDInfo{DID: response.DID, VerKey: response.VerKey}
+	if err := did.StoreTheirDID(i.wallet, theirDID); err != nil {
+		return nil, fmt.Errorf("error storing DID [%s]: %s", response.DID, err)
+	}
+	if err := pairwise.Create(i.wallet, response.DID, request.DID, nil); err != nil {
+		return nil, fmt.Errorf("error creating pairwise for DID [%s]: %s", response.DID, err)
+	}
+
+	if i.pool != nil {
+		if err := sendNYM(i.pool, i.wallet, i.submitterDID, &did.Info{DID: response.DID, VerKey: response.VerKey}); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debugf("Accepted connection response from DID [%s] for DID [%s] - Wallet [%s]", response.DID, request.DID, i.wallet.Name)
+	return &Connection{
+		MyDID:       request.DID,
+		MyVerKey:    myVerKey,
+		TheirDID:    response.DID,
+		TheirVerKey: response.VerKey,
+	}, nil
+}
+
+// Invitee is the party that responds to a connection request
+type Invitee struct {
+	wallet *wallet.Wallet
+	pool   *pool.Pool
+}
+
+// NewInvitee returns an invitee that stores the connections in the given wallet. The pool is
+// used to read the inviter's verkey from the ledger if it's not in the request; it may be nil.
+func NewInvitee(wallet *wallet.Wallet, pool *pool.Pool) *Invitee {
+	return &Invitee{wallet: wallet, pool: pool}
+}
+
+// Accept creates a DID for the connection requested by the inviter and returns the anoncrypted
+// connection response that is sent to the inviter. The inviter's DID and the pairwise
+// relationship are stored in the wallet.
+func (i *Invitee) Accept(request *Request) ([]byte, *Connection, error) {
+	if err := validateRequest(request); err != nil {
+		return nil, nil, err
+	}
+
+	theirVerKey := request.VerKey
+	if theirVerKey == "" {
+		if i.pool == nil {
+			return nil, nil, fmt.Errorf("connection request has no verkey and no pool is available to read it from the ledger")
+		}
+		// KeyForDID also stores their DID in the wallet
+		verKey, err := did.KeyForDID(i.pool, i.wallet, request.DID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting key of DID [%s]: %s", request.DID, err)
+		}
+		theirVerKey = verKey
+	} else {
+		// The verkey may be abbreviated, which libindy's crypto functions don't accept
+		verKey, err := util.ExpandVerKey(request.DID, theirVerKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid connection request: %s", err)
+		}
+		theirVerKey = verKey
+		if err := did.StoreTheirDID(i.wallet, &did.TheirDIDInfo{DID: request.DID, VerKey: theirVerKey}); err != nil {
+			return nil, nil, fmt.Errorf("error storing DID [%s]: %s", request.DID, err)
+		}
+	}
+
+	myDID, err := did.CreateAndStoreMyDIDWithOptions(i.wallet, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating DID for connection: %s", err)
+	}
+
+	responseJSON, err := json.Marshal(&Response{DID: myDID.DID, VerKey: myDID.VerKey, Nonce: request.Nonce})
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedResponse, err := crypto.AnonCrypt(theirVerKey, responseJSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encrypting connection response: %s", err)
+	}
+
+	if err := pairwise.Create(i.wallet, request.DID, myDID.DID, nil); err != nil {
+		return nil, nil, fmt.Errorf("error creating pairwise for DID [%s]: %s", request.DID, err)
+	}
+
+	logger.Debugf("Accepted connection request from DID [%s] with DID [%s] - Wallet [%s]", request.DID, myDID.DID, i.wallet.Name)
+	return encryptedResponse, &Connection{
+		MyDID:       myDID.DID,
+		MyVerKey:    myDID.VerKey,
+		TheirDID:    request.DID,
+		TheirVerKey: theirVerKey,
+	}, nil
+}
+
+// NewNonce returns a random 128 bit nonce as a decimal string
+func NewNonce() (string, error) {
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), nonceBits))
+	if err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+func validateRequest(request *Request) error {
+	if request == nil {
+		return fmt.Errorf("connection request must be specified")
+	}
+	if err := util.ValidateDID(request.DID); err != nil {
+		return fmt.Errorf("invalid connection request: %s", err)
+	}
+	if request.VerKey != "" {
+		if err := util.ValidateVerKey(request.VerKey); err != nil {
+			return fmt.Errorf("invalid connection request: %s", err)
+		}
+	}
+	if request.Nonce == "" {
+		return fmt.Errorf("invalid connection request: nonce must be specified")
+	}
+	return nil
+}
+
+func parseResponse(responseJSON []byte) (*Response, error) {
+	response := &Response{}
+	if err := json.Unmarshal(responseJSON, response); err != nil {
+		return nil, fmt.Errorf("invalid connection response: %s", err)
+	}
+	if err := util.ValidateDID(response.DID); err != nil {
+		return nil, fmt.Errorf("invalid connection response: %s", err)
+	}
+	if err := util.ValidateVerKey(response.VerKey); err != nil {
+		return nil, fmt.Errorf("invalid connection response: %s", err)
+	}
+	return response, nil
+}
+
+func sendNYM(pool *pool.Pool, wallet *wallet.Wallet, submitterDID string, info *did.Info) error {
+	request, err := ledger.BuildNYMRequest(submitterDID, info.DID, info.VerKey, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error building NYM request for DID [%s]: %s", info.DID, err)
+	}
+	response, err := ledger.SignAndSubmitRequest(pool, wallet, submitterDID, request)
+	if err != nil {
+		return fmt.Errorf("error sending NYM for DID [%s]: %s", info.DID, err)
+	}
+	return ledger.CheckReply("NYM", response)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/pairwise"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+func TestOnboarding(t *testing.T) {
+	inviterWallet, err := getWallet("connection_wallet1", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer inviterWallet.Close()
+
+	inviteeWallet, err := getWallet("connection_wallet2", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer inviteeWallet.Close()
+
+	inviter := NewInviter(inviterWallet, nil, "")
+	invitee := NewInvitee(inviteeWallet, nil)
+
+	request, err := inviter.CreateRequest()
+	if err != nil {
+		t.Fatalf("Error received from CreateRequest: %s", err)
+	}
+
+	encryptedResponse, inviteeConnection, err := invitee.Accept(request)
+	if err != nil {
+		t.Fatalf("Error received from Accept: %s", err)
+	}
+
+	tampered := *request
+	tampered.Nonce = tampered.Nonce + "1"
+	if _, err := inviter.AcceptResponse(&tampered, encryptedResponse); err == nil {
+		t.Fatalf("Expecting error for response with wrong nonce")
+	}
+
+	inviterConnection, err := inviter.AcceptResponse(request, encryptedResponse)
+	if err != nil {
+		t.Fatalf("Error received from AcceptResponse: %s", err)
+	}
+
+	if inviterConnection.MyDID != inviteeConnection.TheirDID || inviterConnection.TheirDID != inviteeConnection.MyDID {
+		t.Fatalf("Connections don't match: %+v, %+v", inviterConnection, inviteeConnection)
+	}
+	if inviterConnection.MyVerKey != inviteeConnection.TheirVerKey || inviterConnection.TheirVerKey != inviteeConnection.MyVerKey {
+		t.Fatalf("Connection verkeys don't match: %+v, %+v", inviterConnection, inviteeConnection)
+	}
+
+	p, err := pairwise.Get(inviterWallet, inviterConnection.TheirDID)
+	if err != nil {
+		t.Fatalf("Error received from pairwise.Get: %s", err)
+	}
+	if p.MyDID != inviterConnection.MyDID {
+		t.Fatalf("Expecting pairwise with my DID [%s] but got [%s]", inviterConnection.MyDID, p.MyDID)
+	}
+
+	p, err = pairwise.Get(inviteeWallet, inviteeConnection.TheirDID)
+	if err != nil {
+		t.Fatalf("Error received from pairwise.Get: %s", err)
+	}
+	if p.MyDID != inviteeConnection.MyDID {
+		t.Fatalf("Expecting pairwise with my DID [%s] but got [%s]", inviteeConnection.MyDID, p.MyDID)
+	}
+}
+
+func TestAcceptAbbreviatedVerKey(t *testing.T) {
+	inviterWallet, err := getWallet("connection_wallet3", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer inviterWallet.Close()
+
+	inviteeWallet, err := getWallet("connection_wallet4", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer inviteeWallet.Close()
+
+	inviter := NewInviter(inviterWallet, nil, "")
+	invitee := NewInvitee(inviteeWallet, nil)
+
+	request, err := inviter.CreateRequest()
+	if err != nil {
+		t.Fatalf("Error received from CreateRequest: %s", err)
+	}
+	verKey := request.VerKey
+	if request.VerKey, err = util.AbbreviateVerKey(request.DID, verKey); err != nil {
+		t.Fatalf("Error received from AbbreviateVerKey: %s", err)
+	}
+
+	encryptedResponse, inviteeConnection, err := invitee.Accept(request)
+	if err != nil {
+		t.Fatalf("Error received from Accept: %s", err)
+	}
+	if inviteeConnection.TheirVerKey != verKey {
+		t.Fatalf("Expecting their verkey [%s] but got [%s]", verKey, inviteeConnection.TheirVerKey)
+	}
+	if _, err := inviter.AcceptResponse(request, encryptedResponse); err != nil {
+		t.Fatalf("Error received from AcceptResponse: %s", err)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	request := &Request{DID: "VsKV7grR1BUE29mG2Fm2kX", VerKey: "GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa", Nonce: "123"}
+	if err := validateRequest(request); err != nil {
+		t.Fatalf("Error received from validateRequest: %s", err)
+	}
+
+	request.VerKey = "~HYwqs2vrTc8Tn4uBV7NBTe"
+	if err := validateRequest(request); err != nil {
+		t.Fatalf("Error received from validateRequest for abbreviated verkey: %s", err)
+	}
+
+	request.VerKey = ""
+	if err := validateRequest(request); err != nil {
+		t.Fatalf("Error received from validateRequest without verkey: %s", err)
+	}
+
+	if err := validateRequest(nil); err == nil {
+		t.Fatalf("Expecting error for nil request")
+	}
+	if err := validateRequest(&Request{DID: "invalid", Nonce: "123"}); err == nil {
+		t.Fatalf("Expecting error for invalid DID")
+	}
+	if err := validateRequest(&Request{DID: "VsKV7grR1BUE29mG2Fm2kX", VerKey: "invalid", Nonce: "123"}); err == nil {
+		t.Fatalf("Expecting error for invalid verkey")
+	}
+	if err := validateRequest(&Request{DID: "VsKV7grR1BUE29mG2Fm2kX"}); err == nil {
+		t.Fatalf("Expecting error for missing nonce")
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	response, err := parseResponse([]byte(`{"did":"VsKV7grR1BUE29mG2Fm2kX","verkey":"GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa","nonce":"123"}`))
+	if err != nil {
+		t.Fatalf("Error received from parseResponse: %s", err)
+	}
+	if response.DID != "VsKV7grR1BUE29mG2Fm2kX" || response.Nonce != "123" {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+
+	if _, err := parseResponse([]byte(`not json`)); err == nil {
+		t.Fatalf("Expecting error for invalid json")
+	}
+	if _, err := parseResponse([]byte(`{"did":"VsKV7grR1BUE29mG2Fm2kX","nonce":"123"}`)); err == nil {
+		t.Fatalf("Expecting error for missing verkey")
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	nonce1, err := NewNonce()
+	if err != nil {
+		t.Fatalf("Error received from NewNonce: %s", err)
+	}
+	nonce2, err := NewNonce()
+	if err != nil {
+		t.Fatalf("Error received from NewNonce: %s", err)
+	}
+	if nonce1 == "" || nonce1 == nonce2 {
+		t.Fatalf("Expecting distinct nonces but got [%s] and [%s]", nonce1, nonce2)
+	}
+}
+
+func getWallet(walletName, poolName string) (*wallet.Wallet, error) {
+	err := wallet.Create(poolName, walletName, "", "", "")
+	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		return nil, err
+	}
+	return wallet.Open(walletName, "", "")
+}
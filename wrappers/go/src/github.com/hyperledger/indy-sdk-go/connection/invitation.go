@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/indy-sdk-go/crypto"
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+// InvitationParam is the query parameter of an invitation URL that holds the encoded invitation
+const InvitationParam = "c_i"
+
+// Invitation is a connection invitation. It carries the connection request of the inviter
+// together with the information the invitee needs to reach the inviter.
+type Invitation struct {
+	// DID is the inviter's DID for the connection
+	DID string `json:"did"`
+
+	// VerKey is the verkey of the inviter's DID. The invitation is signed with this key.
+	VerKey string `json:"verkey"`
+
+	// Endpoint is the address the connection response is sent to
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Label is a human readable name of the inviter
+	Label string `json:"label,omitempty"`
+
+	// Nonce is the nonce that the invitee returns in the response
+	Nonce string `json:"nonce"`
+}
+
+// Request returns the connection request of the invitation
+func (inv *Invitation) Request() *Request {
+	return &Request{DID: inv.DID, VerKey: inv.VerKey, Nonce: inv.Nonce}
+}
+
+// CreateInvitation creates a connection request like CreateRequest and returns it as an
+// invitation with the given label and endpoint. The invitation must be kept to accept the
+// response, which is done by passing invitation.Request() to AcceptResponse.
+func (i *Inviter) CreateInvitation(label, endpoint string) (*Invitation, error) {
+	request, err := i.CreateRequest()
+	if err != nil {
+		return nil, err
+	}
+	return &Invitation{
+		DID:      request.DID,
+		VerKey:   request.VerKey,
+		Endpoint: endpoint,
+		Label:    label,
+		Nonce:    request.Nonce,
+	}, nil
+}
+
+// EncodeInvitation signs the invitation with the key of its verkey, which must be in the wallet,
+// and returns it in compact form: the base64url encoded json of the invitation and the base64url
+// encoded signature separated by a '.'. The result may be embedded in URLs and QR codes as is.
+func EncodeInvitation(wallet *wallet.Wallet, invitation *Invitation) (string, error) {
+	if err := validateInvitation(invitation); err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(invitation)
+	if err != nil {
+		return "", err
+	}
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := crypto.Sign(wallet, invitation.VerKey, []byte(encodedPayload))
+	if err != nil {
+		return "", fmt.Errorf("error signing invitation: %s", err)
+	}
+
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// DecodeInvitation decodes an invitation encoded with EncodeInvitation and verifies its signature.
+//
+// The signature proves that the invitation was not modified after it was signed by the holder of
+// its verkey. It doesn't prove who that holder is: the invitee should check the verkey against the
+// ledger (see did.KeyForDID) if the inviter's DID is expected to be there.
+func DecodeInvitation(encoded string) (*Invitation, error) {
+	parts := strings.Split(encoded, ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid invitation: expecting payload and signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation payload: %s", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation signature: %s", err)
+	}
+
+	invitation := &Invitation{}
+	if err := json.Unmarshal(payload, invitation); err != nil {
+		return nil, fmt.Errorf("invalid invitation payload: %s", err)
+	}
+	if err := validateInvitation(invitation); err != nil {
+		return nil, err
+	}
+
+	verKey, err := util.ExpandVerKey(invitation.DID, invitation.VerKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invitation: %s", err)
+	}
+	valid, err := crypto.Verify(verKey, []byte(parts[0]), signature)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying invitation signature: %s", err)
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid invitation signature")
+	}
+
+	return invitation, nil
+}
+
+// InvitationURL returns the URL of an encoded invitation, which is the base URL
+// (usually the inviter's endpoint) with the invitation in the 'c_i' query parameter.
+func InvitationURL(baseURL, encoded string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid invitation base URL: %s", err)
+	}
+	query := u.Query()
+	query.Set(InvitationParam, encoded)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// ParseInvitationURL returns the encoded invitation of an invitation URL
+func ParseInvitationURL(invitationURL string) (string, error) {
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid invitation URL: %s", err)
+	}
+	encoded := u.Query().Get(InvitationParam)
+	if encoded == "" {
+		return "", fmt.Errorf("invitation URL has no '%s' parameter", InvitationParam)
+	}
+	return encoded, nil
+}
+
+func validateInvitation(invitation *Invitation) error {
+	if invitation == nil {
+		return fmt.Errorf("invitation must be specified")
+	}
+	if invitation.VerKey == "" {
+		return fmt.Errorf("invalid invitation: verkey must be specified")
+	}
+	if err := validateRequest(invitation.Request()); err != nil {
+		return fmt.Errorf("invalid invitation: %s", err)
+	}
+	return nil
+}
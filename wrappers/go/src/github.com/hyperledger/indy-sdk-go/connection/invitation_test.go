@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestInvitation(t *testing.T) {
+	inviterWallet, err := getWallet("connection_wallet1", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer inviterWallet.Close()
+
+	inviteeWallet, err := getWallet("connection_wallet2", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer inviteeWallet.Close()
+
+	inviter := NewInviter(inviterWallet, nil, "")
+	invitation, err := inviter.CreateInvitation("Faber College", "http://faber.example.com:8020")
+	if err != nil {
+		t.Fatalf("Error received from CreateInvitation: %s", err)
+	}
+
+	encoded, err := EncodeInvitation(inviterWallet, invitation)
+	if err != nil {
+		t.Fatalf("Error received from EncodeInvitation: %s", err)
+	}
+
+	invitationURL, err := InvitationURL(invitation.Endpoint, encoded)
+	if err != nil {
+		t.Fatalf("Error received from InvitationURL: %s", err)
+	}
+	encodedFromURL, err := ParseInvitationURL(invitationURL)
+	if err != nil {
+		t.Fatalf("Error received from ParseInvitationURL: %s", err)
+	}
+	if encodedFromURL != encoded {
+		t.Fatalf("Expecting invitation [%s] from URL but got [%s]", encoded, encodedFromURL)
+	}
+
+	decoded, err := DecodeInvitation(encodedFromURL)
+	if err != nil {
+		t.Fatalf("Error received from DecodeInvitation: %s", err)
+	}
+	if *decoded != *invitation {
+		t.Fatalf("Expecting invitation %+v but got %+v", invitation, decoded)
+	}
+
+	tampered := *invitation
+	tampered.Endpoint = "http://attacker.example.com"
+	tamperedEncoded, err := EncodeInvitation(inviterWallet, &tampered)
+	if err != nil {
+		t.Fatalf("Error received from EncodeInvitation: %s", err)
+	}
+	parts := strings.Split(tamperedEncoded, ".")
+	if _, err := DecodeInvitation(parts[0] + "." + strings.Split(encoded, ".")[1]); err == nil {
+		t.Fatalf("Expecting error for tampered invitation")
+	}
+
+	encryptedResponse, _, err := NewInvitee(inviteeWallet, nil).Accept(decoded.Request())
+	if err != nil {
+		t.Fatalf("Error received from Accept: %s", err)
+	}
+	if _, err := inviter.AcceptResponse(invitation.Request(), encryptedResponse); err != nil {
+		t.Fatalf("Error received from AcceptResponse: %s", err)
+	}
+}
+
+func TestDecodeInvalidInvitation(t *testing.T) {
+	signature := base64.RawURLEncoding.EncodeToString([]byte("signature"))
+	invalid := []string{
+		"",
+		"payload",
+		"a.b.c",
+		"!!!." + signature,
+		base64.RawURLEncoding.EncodeToString([]byte("not json")) + "." + signature,
+		base64.RawURLEncoding.EncodeToString([]byte(`{"did":"VsKV7grR1BUE29mG2Fm2kX","nonce":"123"}`)) + "." + signature,
+		base64.RawURLEncoding.EncodeToString([]byte(`{"did":"VsKV7grR1BUE29mG2Fm2kX","verkey":"GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa"}`)) + "." + signature,
+	}
+	for _, encoded := range invalid {
+		if _, err := DecodeInvitation(encoded); err == nil {
+			t.Fatalf("Expecting error for invitation [%s]", encoded)
+		}
+	}
+}
+
+func TestInvitationURL(t *testing.T) {
+	invitationURL, err := InvitationURL("https://faber.example.com/connect?lang=en", "abc.def")
+	if err != nil {
+		t.Fatalf("Error received from InvitationURL: %s", err)
+	}
+	encoded, err := ParseInvitationURL(invitationURL)
+	if err != nil {
+		t.Fatalf("Error received from ParseInvitationURL: %s", err)
+	}
+	if encoded != "abc.def" {
+		t.Fatalf("Expecting invitation [abc.def] but got [%s]", encoded)
+	}
+
+	if _, err := ParseInvitationURL("https://faber.example.com/connect"); err == nil {
+		t.Fatalf("Expecting error for URL without invitation")
+	}
+}
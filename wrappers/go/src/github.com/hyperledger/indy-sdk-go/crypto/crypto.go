@@ -11,6 +11,7 @@ import (
 
 	"github.com/hyperledger/indy-sdk-go/common/callback"
 	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/crypto/verify"
 	"github.com/hyperledger/indy-sdk-go/indy"
 	"github.com/hyperledger/indy-sdk-go/wallet"
 )
@@ -105,6 +106,45 @@ func AuthDecrypt(wallet *wallet.Wallet, recipientVK string, message []byte) (sen
 	return
 }
 
+// Sign signs a message with a key in the wallet.
+// Note to use DID keys with this function you can call indy_key_for_did to get key id (verkey)
+// for specific DID.
+//
+// wallet   The wallet.
+// signerVK Id (verkey) of my key. The key must be created by calling createKey or createAndStoreMyDid
+// message  The message to be signed
+func Sign(wallet *wallet.Wallet, signerVK string, message []byte) (signature []byte, err error) {
+	respChan, errChan := sign(wallet, signerVK, message)
+	select {
+	case signature = <-respChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// Verify verifies a signature created with a key associated with the given verkey.
+// It returns false without error if the signature is not valid for the message.
+// Ed25519 signatures are verified in Go (see package verify) if supported, otherwise by libindy.
+//
+// signerVK  Verkey of the message signer
+// message   The signed message
+// signature The signature of the message
+func Verify(signerVK string, message, signature []byte) (valid bool, err error) {
+	if len(signature) > 0 {
+		valid, err = verify.Verify(signerVK, message, signature)
+		if err != verify.ErrUnsupported {
+			return
+		}
+	}
+
+	respChan, errChan := verifyWithIndy(signerVK, message, signature)
+	select {
+	case valid = <-respChan:
+	case err = <-errChan:
+	}
+	return
+}
+
 func anonCrypt(recipientVK string, message []byte) (chan []byte, chan error) {
 	logger.Debugf("Anonymously encrypting message - RecipientVK [%s] - Message: [%s]", recipientVK, message)
 
@@ -156,7 +196,13 @@ func anonDecrypt(wallet *wallet.Wallet, recipientVK string, encryptedMsg []byte)
 		}
 	}
 
-	err := indy.AnonDecrypt(wallet.Handle(), recipientVK, encryptedMsg, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.AnonDecrypt(walletHandle, recipientVK, encryptedMsg, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -188,7 +234,13 @@ func authCrypt(wallet *wallet.Wallet, senderVK, recipientVK string, message []by
 		}
 	}
 
-	err := indy.AuthCrypt(wallet.Handle(), senderVK, recipientVK, message, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.AuthCrypt(walletHandle, senderVK, recipientVK, message, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -225,7 +277,79 @@ func authDecrypt(wallet *wallet.Wallet, recipientVK string, message []byte) (cha
 		}
 	}
 
-	err := indy.AuthDecrypt(wallet.Handle(), recipientVK, message, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.AuthDecrypt(walletHandle, recipientVK, message, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return respChan, errChan
+}
+
+func sign(wallet *wallet.Wallet, signerVK string, message []byte) (chan []byte, chan error) {
+	logger.Debugf("Signing message - Wallet [%s], SignerVK [%s] - Message: [%s]", wallet.Name, signerVK, message)
+
+	respChan := make(chan []byte)
+	errChan := make(chan error, 1)
+
+	if signerVK == "" {
+		errChan <- fmt.Errorf("signer verification key must be specified")
+		return respChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			respChan <- data.([]byte)
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.Sign(walletHandle, signerVK, message, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return respChan, errChan
+}
+
+func verifyWithIndy(signerVK string, message, signature []byte) (chan bool, chan error) {
+	logger.Debugf("Verifying signature - SignerVK [%s] - Message: [%s], Signature: [%#x]", signerVK, message, signature)
+
+	respChan := make(chan bool)
+	errChan := make(chan error, 1)
+
+	if signerVK == "" {
+		errChan <- fmt.Errorf("signer verification key must be specified")
+		return respChan, errChan
+	}
+	if len(signature) == 0 {
+		errChan <- fmt.Errorf("signature must be specified")
+		return respChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			respChan <- data.(bool)
+		}
+	}
+
+	err := indy.Verify(signerVK, message, signature, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
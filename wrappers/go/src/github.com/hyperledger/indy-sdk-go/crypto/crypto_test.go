@@ -7,7 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package crypto
 
 import (
+	"encoding/json"
 	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/wallet"
 )
 
 const (
@@ -23,3 +27,157 @@ func TestAnonCrypt(t *testing.T) {
 	}
 	t.Logf("Got encrypted message [%#x]", encryptedMsg)
 }
+
+type keyMetadata struct {
+	Purpose string `json:"purpose"`
+}
+
+func TestCreateKey(t *testing.T) {
+	w, err := getWallet("crypto_wallet1", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	seed := []byte("00000000000000000000000000000My1")
+	verKey, err := CreateKey(w, &KeyOptions{Seed: seed, CryptoType: CryptoTypeEd25519})
+	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		t.Fatalf("Error received from CreateKey: %s", err)
+	}
+	if err == nil && verKey != "GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa" {
+		t.Fatalf("Expecting verkey [GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa] but got [%s]", verKey)
+	}
+	verKey = "GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa"
+
+	randomVerKey, err := CreateKey(w, nil)
+	if err != nil {
+		t.Fatalf("Error received from CreateKey: %s", err)
+	}
+	if randomVerKey == "" || randomVerKey == verKey {
+		t.Fatalf("Unexpected random verkey [%s]", randomVerKey)
+	}
+
+	if err := GetKeyMetadata(w, randomVerKey, &keyMetadata{}); indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletNotFoundError), err)
+	}
+
+	if err := SetKeyMetadata(w, verKey, &keyMetadata{Purpose: "transport"}); err != nil {
+		t.Fatalf("Error received from SetKeyMetadata: %s", err)
+	}
+	metadata := &keyMetadata{}
+	if err := GetKeyMetadata(w, verKey, metadata); err != nil {
+		t.Fatalf("Error received from GetKeyMetadata: %s", err)
+	}
+	if metadata.Purpose != "transport" {
+		t.Fatalf("Expecting purpose [transport] but got [%s]", metadata.Purpose)
+	}
+
+	if _, err := CreateKey(w, &KeyOptions{Seed: []byte("short")}); err == nil {
+		t.Fatalf("Expecting error for invalid seed")
+	}
+}
+
+func TestKeyOptions(t *testing.T) {
+	options := &KeyOptions{Seed: []byte("00000000000000000000000000000My1")}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("Error received from Validate: %s", err)
+	}
+	keyJSON, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("Error received from Marshal: %s", err)
+	}
+	if string(keyJSON) != `{"seed":"MDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDBNeTE="}` {
+		t.Fatalf("Unexpected key json %s", keyJSON)
+	}
+
+	keyJSON, err = json.Marshal(&KeyOptions{})
+	if err != nil {
+		t.Fatalf("Error received from Marshal: %s", err)
+	}
+	if string(keyJSON) != `{}` {
+		t.Fatalf("Unexpected key json %s", keyJSON)
+	}
+
+	if err := (&KeyOptions{Seed: []byte("short")}).Validate(); err == nil {
+		t.Fatalf("Expecting error for invalid seed")
+	}
+	if err := (&KeyOptions{CryptoType: "secp256k1"}).Validate(); err == nil {
+		t.Fatalf("Expecting error for unsupported crypto type")
+	}
+}
+
+func TestSign(t *testing.T) {
+	w, err := getWallet("crypto_wallet1", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	verKey, err := CreateKey(w, nil)
+	if err != nil {
+		t.Fatalf("Error received from CreateKey: %s", err)
+	}
+
+	message := []byte("some message")
+	signature, err := Sign(w, verKey, message)
+	if err != nil {
+		t.Fatalf("Error received from Sign: %s", err)
+	}
+
+	valid, err := Verify(verKey, message, signature)
+	if err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+	if !valid {
+		t.Fatalf("Expecting signature to be valid")
+	}
+
+	respChan, errChan := verifyWithIndy(verKey, message, signature)
+	select {
+	case valid = <-respChan:
+	case err = <-errChan:
+	}
+	if err != nil {
+		t.Fatalf("Error received from verifyWithIndy: %s", err)
+	}
+	if !valid {
+		t.Fatalf("Expecting signature to be valid for libindy")
+	}
+
+	valid, err = Verify(verKey, []byte("other message"), signature)
+	if err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+	if valid {
+		t.Fatalf("Expecting signature to be invalid for other message")
+	}
+
+	if _, err := Sign(w, verKey1, message); err == nil {
+		t.Fatalf("Expecting error for key that isn't in the wallet")
+	}
+}
+
+func TestClosedWallet(t *testing.T) {
+	w, err := getWallet("closed_wallet", "pool1")
+	if err != nil {
+		t.Fatalf("Error received from getWallet: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+
+	if _, err := CreateKey(w, nil); err != wallet.ErrClosed {
+		t.Fatalf("Expecting error [%s] from CreateKey but got [%v]", wallet.ErrClosed, err)
+	}
+	if _, err := Sign(w, verKey1, []byte("message")); err != wallet.ErrClosed {
+		t.Fatalf("Expecting error [%s] from Sign but got [%v]", wallet.ErrClosed, err)
+	}
+}
+
+func getWallet(walletName, poolName string) (*wallet.Wallet, error) {
+	err := wallet.Create(poolName, walletName, "", "", "")
+	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		return nil, err
+	}
+	return wallet.Open(walletName, "", "")
+}
@@ -0,0 +1,203 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package crypto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/common/callback"
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/indy"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+const (
+	// SeedSize is the size of a key seed in bytes
+	SeedSize = util.SeedSize
+
+	// CryptoTypeEd25519 is the ed25519 crypto type, which is the default and only supported type
+	CryptoTypeEd25519 = util.CryptoTypeEd25519
+)
+
+// KeyOptions are the options for creating a key with CreateKey
+type KeyOptions struct {
+	// Seed is the seed of the key. It must be SeedSize bytes. A random seed is used if nil.
+	Seed []byte
+
+	// CryptoType is the type of the key. Defaults to CryptoTypeEd25519.
+	CryptoType string
+}
+
+// Validate returns an error if the options are invalid
+func (o *KeyOptions) Validate() error {
+	if err := util.ValidateSeed(o.Seed); err != nil {
+		return err
+	}
+	return util.ValidateCryptoType(o.CryptoType)
+}
+
+// MarshalJSON returns the key json expected by libindy
+func (o *KeyOptions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Seed       string `json:"seed,omitempty"`
+		CryptoType string `json:"crypto_type,omitempty"`
+	}{
+		Seed:       util.EncodeSeed(o.Seed),
+		CryptoType: o.CryptoType,
+	})
+}
+
+// CreateKey creates a key pair that isn't tied to a DID and stores it in the wallet.
+// It returns the verkey of the key pair, which is also the identifier of the key.
+//
+// wallet  The wallet.
+// options The options of the key. May be nil for a random ed25519 key.
+func CreateKey(wallet *wallet.Wallet, options *KeyOptions) (verKey string, err error) {
+	verKeyChan, errChan := createKey(wallet, options)
+	select {
+	case verKey = <-verKeyChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// SetKeyMetadata saves the metadata of a key in the wallet, replacing any existing metadata.
+// The metadata is stored as json.
+//
+// wallet   The wallet.
+// verKey   The verkey of the key.
+// metadata The metadata. Any value that can be marshaled to json.
+func SetKeyMetadata(wallet *wallet.Wallet, verKey string, metadata interface{}) error {
+	return <-setKeyMetadata(wallet, verKey, metadata)
+}
+
+// GetKeyMetadata retrieves the metadata of a key from the wallet and decodes it into the given value.
+// An error with code WalletNotFoundError is returned if no metadata is stored for the key.
+//
+// wallet   The wallet.
+// verKey   The verkey of the key.
+// metadata A pointer to the value into which the json metadata is decoded.
+func GetKeyMetadata(wallet *wallet.Wallet, verKey string, metadata interface{}) error {
+	metaChan, errChan := getKeyMetadata(wallet, verKey)
+	select {
+	case meta := <-metaChan:
+		if err := json.Unmarshal([]byte(meta), metadata); err != nil {
+			return fmt.Errorf("invalid metadata of key [%s]: %s", verKey, err)
+		}
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+func createKey(wallet *wallet.Wallet, options *KeyOptions) (chan string, chan error) {
+	logger.Debugf("Creating key - Wallet [%s]", wallet.Name)
+
+	verKeyChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if options == nil {
+		options = &KeyOptions{}
+	}
+	if err := options.Validate(); err != nil {
+		errChan <- err
+		return verKeyChan, errChan
+	}
+	keyJSON, err := json.Marshal(options)
+	if err != nil {
+		errChan <- err
+		return verKeyChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			verKeyChan <- data.(string)
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return verKeyChan, errChan
+	}
+
+	err = indy.CreateKey(walletHandle, string(keyJSON), cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return verKeyChan, errChan
+}
+
+func setKeyMetadata(wallet *wallet.Wallet, verKey string, metadata interface{}) chan error {
+	logger.Debugf("Setting metadata of key [%s] - Wallet [%s]", verKey, wallet.Name)
+
+	errChan := make(chan error, 1)
+
+	if verKey == "" {
+		errChan <- fmt.Errorf("verification key must be specified")
+		return errChan
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		errChan <- fmt.Errorf("error marshalling metadata of key [%s]: %s", verKey, err)
+		return errChan
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.SetKeyMetadata(walletHandle, verKey, string(metadataJSON), callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
+
+func getKeyMetadata(wallet *wallet.Wallet, verKey string) (chan string, chan error) {
+	logger.Debugf("Getting metadata of key [%s] - Wallet [%s]", verKey, wallet.Name)
+
+	metaChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if verKey == "" {
+		errChan <- fmt.Errorf("verification key must be specified")
+		return metaChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			metaChan <- data.(string)
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return metaChan, errChan
+	}
+
+	err = indy.GetKeyMetadata(walletHandle, verKey, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return metaChan, errChan
+}
@@ -0,0 +1,18 @@
+//go:build go1.13
+// +build go1.13
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verify
+
+import (
+	"crypto/ed25519"
+)
+
+func verifyEd25519(publicKey, message, signature []byte) (bool, error) {
+	return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature), nil
+}
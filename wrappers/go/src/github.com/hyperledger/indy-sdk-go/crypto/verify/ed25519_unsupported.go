@@ -0,0 +1,15 @@
+//go:build !go1.13
+// +build !go1.13
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verify
+
+// crypto/ed25519 was added to the standard library in Go 1.13
+func verifyEd25519(publicKey, message, signature []byte) (bool, error) {
+	return false, ErrUnsupported
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/indy-sdk-go/did/util"
+)
+
+const (
+	// PublicKeySize is the size of an ed25519 public key (the decoded verkey) in bytes
+	PublicKeySize = 32
+
+	// SignatureSize is the size of an ed25519 signature in bytes
+	SignatureSize = 64
+
+	cryptoTypeEd25519 = "ed25519"
+)
+
+// ErrUnsupported is returned if a signature can't be verified in Go, either because the verkey
+// isn't an ed25519 key or because the Go version has no ed25519 support (Go 1.13 or later is
+// required). Such signatures can still be verified by libindy with crypto.Verify.
+var ErrUnsupported = errors.New("signature verification is not supported in Go")
+
+// Verify verifies an ed25519 signature created by libindy (see crypto.Sign) without libindy,
+// so it needs neither a wallet nor cgo. It returns false without error if the signature is not
+// valid for the message.
+//
+// signerVK  Verkey of the message signer. It must be a full verkey, optionally with the ':ed25519' suffix.
+// message   The signed message
+// signature The signature of the message
+func Verify(signerVK string, message, signature []byte) (bool, error) {
+	publicKey, err := publicKey(signerVK)
+	if err != nil {
+		return false, err
+	}
+	if len(signature) != SignatureSize {
+		return false, nil
+	}
+	return verifyEd25519(publicKey, message, signature)
+}
+
+// publicKey returns the ed25519 public key of a verkey
+func publicKey(verKey string) ([]byte, error) {
+	if verKey == "" {
+		return nil, fmt.Errorf("signer verification key must be specified")
+	}
+	if util.IsAbbreviated(verKey) {
+		return nil, fmt.Errorf("verkey [%s] is abbreviated: the full verkey is required", verKey)
+	}
+	if i := strings.Index(verKey, ":"); i >= 0 {
+		if verKey[i+1:] != cryptoTypeEd25519 {
+			return nil, ErrUnsupported
+		}
+		verKey = verKey[:i]
+	}
+
+	key, err := util.DecodeBase58(verKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verkey [%s]: %s", verKey, err)
+	}
+	if len(key) != PublicKeySize {
+		return nil, fmt.Errorf("invalid verkey [%s]: expecting %d bytes but got %d", verKey, PublicKeySize, len(key))
+	}
+	return key, nil
+}
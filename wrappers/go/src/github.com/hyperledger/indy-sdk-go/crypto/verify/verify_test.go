@@ -0,0 +1,83 @@
+//go:build go1.13
+// +build go1.13
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verify
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/did/util"
+)
+
+// Test vector 2 of RFC 8032, section 7.1
+const (
+	publicKeyHex = "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"
+	messageHex   = "72"
+	signatureHex = "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"
+)
+
+func TestVerify(t *testing.T) {
+	publicKey, _ := hex.DecodeString(publicKeyHex)
+	message, _ := hex.DecodeString(messageHex)
+	signature, _ := hex.DecodeString(signatureHex)
+	verKey := util.EncodeBase58(publicKey)
+
+	valid, err := Verify(verKey, message, signature)
+	if err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+	if !valid {
+		t.Fatalf("Expecting signature to be valid")
+	}
+
+	valid, err = Verify(verKey+":ed25519", message, signature)
+	if err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+	if !valid {
+		t.Fatalf("Expecting signature to be valid for verkey with crypto type")
+	}
+
+	valid, err = Verify(verKey, []byte("other message"), signature)
+	if err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+	if valid {
+		t.Fatalf("Expecting signature to be invalid for other message")
+	}
+
+	valid, err = Verify(verKey, message, signature[:SignatureSize-1])
+	if err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+	if valid {
+		t.Fatalf("Expecting short signature to be invalid")
+	}
+}
+
+func TestVerifyInvalidVerKey(t *testing.T) {
+	signature, _ := hex.DecodeString(signatureHex)
+
+	if _, err := Verify("", nil, signature); err == nil {
+		t.Fatalf("Expecting error for missing verkey")
+	}
+	if _, err := Verify("~HYwqs2vrTc8Tn4uBV7NBTe", nil, signature); err == nil {
+		t.Fatalf("Expecting error for abbreviated verkey")
+	}
+	if _, err := Verify("0OIl", nil, signature); err == nil {
+		t.Fatalf("Expecting error for invalid base58 verkey")
+	}
+	if _, err := Verify("HYwqs2vrTc8Tn4uBV7NBTe", nil, signature); err == nil {
+		t.Fatalf("Expecting error for short verkey")
+	}
+	if _, err := Verify("GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa:secp256k1", nil, signature); err != ErrUnsupported {
+		t.Fatalf("Expecting error [%s] but got [%v]", ErrUnsupported, err)
+	}
+}
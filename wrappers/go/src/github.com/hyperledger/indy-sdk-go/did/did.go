@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package did
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/hyperledger/indy-sdk-go/pool"
 
 	"github.com/hyperledger/indy-sdk-go/common/callback"
 	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/did/util"
 	"github.com/hyperledger/indy-sdk-go/indy"
 	"github.com/hyperledger/indy-sdk-go/wallet"
 )
@@ -25,6 +27,25 @@ type Info struct {
 	VerKey string `json:"verkey"`
 }
 
+// TheirDIDInfo is the identity of a DID owned by another party
+type TheirDIDInfo struct {
+	// DID is the DID of the other party
+	DID string `json:"did"`
+
+	// VerKey is the verification key of the DID. It may be omitted if the
+	// DID is a cryptonym, i.e. the DID is the verkey.
+	VerKey string `json:"verkey,omitempty"`
+}
+
+// KeyOptions are the options for generating new keys for a DID
+type KeyOptions struct {
+	// Seed is the seed of the keys. It must be SeedSize bytes. A random seed is used if nil.
+	Seed []byte
+
+	// CryptoType is the type of the keys. Defaults to CryptoTypeEd25519.
+	CryptoType string
+}
+
 // CreateAndStoreMyDID creates keys (signing and encryption keys) for a new
 // DID (owned by the caller of the library).
 // Identity's DID must be either explicitly provided, or taken as the first 16 bit of verkey.
@@ -32,7 +53,7 @@ type Info struct {
 // and encrypt transactions.
 //
 // wallet  The wallet.
-// didJson Identity information as json.
+// didJson Identity information as json. See CreateAndStoreMyDIDWithOptions for typed options.
 func CreateAndStoreMyDID(wallet *wallet.Wallet, didJSON string) (didInfo *Info, err error) {
 	infoChan, errChan := createAndStoreMyDID(wallet, didJSON)
 	select {
@@ -67,6 +88,57 @@ func KeyForDID(pool *pool.Pool, wallet *wallet.Wallet, did string) (key string,
 	return
 }
 
+// StoreTheirDID saves the DID of another party, for example for a pairwise connection,
+// in the wallet so that it can be used to verify transactions.
+//
+// wallet The wallet.
+// info   The DID and verkey of the other party.
+func StoreTheirDID(wallet *wallet.Wallet, info *TheirDIDInfo) error {
+	return <-storeTheirDID(wallet, info)
+}
+
+// KeyForLocalDID returns the ver key (key id) for the given DID from the wallet only.
+//
+// Unlike KeyForDID, the ledger isn't consulted and no freshness checking is done, so
+// the DID must have been created with CreateAndStoreMyDID or stored with StoreTheirDID.
+//
+// wallet The wallet.
+// did    The DID to resolve key.
+func KeyForLocalDID(wallet *wallet.Wallet, did string) (key string, err error) {
+	keyChan, errChan := keyForLocalDID(wallet, did)
+	select {
+	case key = <-keyChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// ReplaceKeysStart generates temporary keys for an existing DID (owned by the caller
+// of the library) and returns the new verkey. The new keys aren't used until they're
+// applied with ReplaceKeysApply, which is typically done after the new verkey has been
+// written to the ledger.
+//
+// wallet  The wallet.
+// did     The DID stored in the wallet.
+// options The options for the new keys. May be nil.
+func ReplaceKeysStart(wallet *wallet.Wallet, did string, options *KeyOptions) (verKey string, err error) {
+	keyChan, errChan := replaceKeysStart(wallet, did, options)
+	select {
+	case verKey = <-keyChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// ReplaceKeysApply applies the temporary keys generated by ReplaceKeysStart as the main
+// keys of the DID.
+//
+// wallet The wallet.
+// did    The DID stored in the wallet.
+func ReplaceKeysApply(wallet *wallet.Wallet, did string) error {
+	return <-replaceKeysApply(wallet, did)
+}
+
 func createAndStoreMyDID(wallet *wallet.Wallet, didJSON string) (chan *Info, chan error) {
 	logger.Debugf("Creating and storing DID - Wallet [%s] - Data: %s", wallet.Name, didJSON)
 
@@ -90,7 +162,13 @@ func createAndStoreMyDID(wallet *wallet.Wallet, didJSON string) (chan *Info, cha
 		}
 	}
 
-	err := indy.CreateAndStoreMyDID(wallet.Handle(), didJSON, cb)
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return didChan, errChan
+	}
+
+	err = indy.CreateAndStoreMyDID(walletHandle, didJSON, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -119,7 +197,18 @@ func keyForDID(pool *pool.Pool, wallet *wallet.Wallet, did string) (chan string,
 		}
 	}
 
-	err := indy.KeyForDID(pool.Handle(), wallet.Handle(), did, cb)
+	poolHandle, err := pool.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return keyChan, errChan
+	}
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return keyChan, errChan
+	}
+
+	err = indy.KeyForDID(poolHandle, walletHandle, did, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -127,3 +216,153 @@ func keyForDID(pool *pool.Pool, wallet *wallet.Wallet, did string) (chan string,
 
 	return keyChan, errChan
 }
+
+func storeTheirDID(wallet *wallet.Wallet, info *TheirDIDInfo) chan error {
+	errChan := make(chan error, 1)
+
+	if info == nil || info.DID == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return errChan
+	}
+
+	if err := util.ValidateDID(info.DID); err != nil {
+		errChan <- err
+		return errChan
+	}
+	if info.VerKey != "" {
+		if err := util.ValidateVerKey(info.VerKey); err != nil {
+			errChan <- err
+			return errChan
+		}
+	}
+
+	logger.Debugf("Storing their DID [%s] - Wallet [%s]", info.DID, wallet.Name)
+
+	identityJSON, err := json.Marshal(info)
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.StoreTheirDID(walletHandle, string(identityJSON), callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
+
+func keyForLocalDID(wallet *wallet.Wallet, did string) (chan string, chan error) {
+	logger.Debugf("Getting local key for DID [%s] - Wallet [%s]", did, wallet.Name)
+
+	keyChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return keyChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			key := data.(string)
+			keyChan <- key
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return keyChan, errChan
+	}
+
+	err = indy.KeyForLocalDID(walletHandle, did, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return keyChan, errChan
+}
+
+func replaceKeysStart(wallet *wallet.Wallet, did string, options *KeyOptions) (chan string, chan error) {
+	logger.Debugf("Starting key replacement for DID [%s] - Wallet [%s]", did, wallet.Name)
+
+	keyChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return keyChan, errChan
+	}
+	if options == nil {
+		options = &KeyOptions{}
+	}
+	if err := options.Validate(); err != nil {
+		errChan <- err
+		return keyChan, errChan
+	}
+
+	identityJSON, err := json.Marshal(options)
+	if err != nil {
+		errChan <- err
+		return keyChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			key := data.(string)
+			keyChan <- key
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return keyChan, errChan
+	}
+
+	err = indy.ReplaceKeysStart(walletHandle, did, string(identityJSON), cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return keyChan, errChan
+}
+
+func replaceKeysApply(wallet *wallet.Wallet, did string) chan error {
+	logger.Debugf("Applying key replacement for DID [%s] - Wallet [%s]", did, wallet.Name)
+
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return errChan
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.ReplaceKeysApply(walletHandle, did, callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
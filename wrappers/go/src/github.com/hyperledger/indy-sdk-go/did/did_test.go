@@ -51,6 +51,221 @@ func TestDID(t *testing.T) {
 	}
 
 	// keyChan, errChan := KeyForDID(p)
+
+	// The typed seed gives the same keys as the string seed
+	stringSeedInfo, err := CreateAndStoreMyDID(w, getJSON("", seed1, "", nil))
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+	seed, err := SeedFromString(seed1)
+	if err != nil {
+		t.Fatalf("Error received from SeedFromString: %s", err)
+	}
+	typedSeedInfo, err := CreateAndStoreMyDIDWithOptions(w, &CreateOptions{DID: "Th7MpTaRZVRYnPiabds81Y", Seed: seed})
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDIDWithOptions: %s", err)
+	}
+	if typedSeedInfo.VerKey != stringSeedInfo.VerKey {
+		t.Fatalf("Expecting verkey [%s] but got [%s]", stringSeedInfo.VerKey, typedSeedInfo.VerKey)
+	}
+}
+
+func TestTheirDID(t *testing.T) {
+	w, err := getWallet("wallet2", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	theirInfo, err := CreateAndStoreMyDID(w, getJSON("", seed2, "", nil))
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+
+	otherWallet, err := getWallet("wallet3", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer otherWallet.Close()
+
+	if err := StoreTheirDID(otherWallet, &TheirDIDInfo{DID: theirInfo.DID, VerKey: theirInfo.VerKey}); err != nil {
+		t.Fatalf("Error received from StoreTheirDID: %s", err)
+	}
+	key, err := KeyForLocalDID(otherWallet, theirInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from KeyForLocalDID: %s", err)
+	}
+	if key != theirInfo.VerKey {
+		t.Fatalf("Expecting key [%s] but got [%s]", theirInfo.VerKey, key)
+	}
+
+	if err := StoreTheirDID(otherWallet, &TheirDIDInfo{}); err == nil {
+		t.Fatalf("Expecting error for missing DID but got success")
+	}
+}
+
+func TestReplaceKeys(t *testing.T) {
+	w, err := getWallet("wallet4", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	didInfo, err := CreateAndStoreMyDID(w, "{}")
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+
+	newKey, err := ReplaceKeysStart(w, didInfo.DID, nil)
+	if err != nil {
+		t.Fatalf("Error received from ReplaceKeysStart: %s", err)
+	}
+	if newKey == didInfo.VerKey {
+		t.Fatalf("Expecting new key to be different from the current key")
+	}
+
+	// The current key is used until the new key is applied
+	key, err := KeyForLocalDID(w, didInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from KeyForLocalDID: %s", err)
+	}
+	if key != didInfo.VerKey {
+		t.Fatalf("Expecting key [%s] but got [%s]", didInfo.VerKey, key)
+	}
+
+	if err := ReplaceKeysApply(w, didInfo.DID); err != nil {
+		t.Fatalf("Error received from ReplaceKeysApply: %s", err)
+	}
+	key, err = KeyForLocalDID(w, didInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from KeyForLocalDID: %s", err)
+	}
+	if key != newKey {
+		t.Fatalf("Expecting key [%s] but got [%s]", newKey, key)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	w, err := getWallet("wallet5", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	didInfo, err := CreateAndStoreMyDID(w, "{}")
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+
+	type profile struct {
+		Label string   `json:"label"`
+		Tags  []string `json:"tags"`
+	}
+	if err := SetMetadata(w, didInfo.DID, &profile{Label: "Alice", Tags: []string{"primary"}}); err != nil {
+		t.Fatalf("Error received from SetMetadata: %s", err)
+	}
+
+	p := &profile{}
+	if err := GetMetadata(w, didInfo.DID, p); err != nil {
+		t.Fatalf("Error received from GetMetadata: %s", err)
+	}
+	if p.Label != "Alice" || len(p.Tags) != 1 {
+		t.Fatalf("Unexpected metadata %+v", p)
+	}
+
+	didWithMeta, err := Get(w, didInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from Get: %s", err)
+	}
+	if didWithMeta.VerKey != didInfo.VerKey {
+		t.Fatalf("Expecting verkey [%s] but got [%s]", didInfo.VerKey, didWithMeta.VerKey)
+	}
+
+	dids, err := List(w)
+	if err != nil {
+		t.Fatalf("Error received from List: %s", err)
+	}
+	found := false
+	for _, d := range dids {
+		if d.DID == didInfo.DID {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expecting DID [%s] in list", didInfo.DID)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	w, err := getWallet("wallet6", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	didInfo, err := CreateAndStoreMyDID(w, "{}")
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+
+	if _, err := GetEndpoint(w, didInfo.DID); indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletNotFoundError), err)
+	}
+
+	if err := SetEndpoint(w, didInfo.DID, &Endpoint{Address: "127.0.0.1:9700", VerKey: didInfo.VerKey}); err != nil {
+		t.Fatalf("Error received from SetEndpoint: %s", err)
+	}
+	endpoint, err := GetEndpoint(w, didInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from GetEndpoint: %s", err)
+	}
+	if endpoint.Address != "127.0.0.1:9700" || endpoint.VerKey != didInfo.VerKey {
+		t.Fatalf("Unexpected endpoint %+v", endpoint)
+	}
+
+	if err := SetEndpoint(w, didInfo.DID, &Endpoint{}); err == nil {
+		t.Fatalf("Expecting error for missing address")
+	}
+}
+
+func TestAsDIDsWithMeta(t *testing.T) {
+	dids, err := asDIDsWithMeta(`[{"did":"VsKV7grR1BUE29mG2Fm2kZ","verkey":"GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa","metadata":"{\"label\":\"Alice\"}"},{"did":"Th7MpTaRZVRYnPiabds81Y","verkey":"FYmoFw55GeQH7SRFa37dkx1d2dZ3zUF8ckg7wmL7ofN4","metadata":null}]`)
+	if err != nil {
+		t.Fatalf("Error received from asDIDsWithMeta: %s", err)
+	}
+	if len(dids) != 2 {
+		t.Fatalf("Expecting 2 DIDs but got %d", len(dids))
+	}
+
+	var meta struct {
+		Label string `json:"label"`
+	}
+	if err := dids[0].UnmarshalMetadata(&meta); err != nil {
+		t.Fatalf("Error received from UnmarshalMetadata: %s", err)
+	}
+	if meta.Label != "Alice" {
+		t.Fatalf("Expecting label [Alice] but got [%s]", meta.Label)
+	}
+	if err := dids[1].UnmarshalMetadata(&meta); err == nil {
+		t.Fatalf("Expecting error for DID without metadata")
+	}
+}
+
+func TestClosedWallet(t *testing.T) {
+	w, err := getWallet("closed_wallet", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+
+	if _, err := CreateAndStoreMyDID(w, "{}"); err != wallet.ErrClosed {
+		t.Fatalf("Expecting error [%s] from CreateAndStoreMyDID but got [%v]", wallet.ErrClosed, err)
+	}
+	if _, err := KeyForLocalDID(w, did1); err != wallet.ErrClosed {
+		t.Fatalf("Expecting error [%s] from KeyForLocalDID but got [%v]", wallet.ErrClosed, err)
+	}
 }
 
 func getWallet(walletName, poolName string) (*wallet.Wallet, error) {
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/common/callback"
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/common/types"
+	"github.com/hyperledger/indy-sdk-go/indy"
+	"github.com/hyperledger/indy-sdk-go/pool"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+// noPoolHandle is passed to libindy to look up endpoints in the wallet only
+const noPoolHandle types.Handle = -1
+
+// Endpoint is the endpoint of a DID. The json format is the format of
+// the 'endpoint' attribute (ATTRIB) of a DID on the ledger.
+type Endpoint struct {
+	// Address is the address of the endpoint, e.g. 127.0.0.1:9700
+	Address string `json:"ha"`
+
+	// VerKey is the transport key of the endpoint. May be empty.
+	VerKey string `json:"verkey,omitempty"`
+}
+
+// SetEndpoint saves or replaces the endpoint of a DID in the wallet.
+//
+// wallet   The wallet.
+// did      The DID.
+// endpoint The endpoint of the DID.
+func SetEndpoint(wallet *wallet.Wallet, did string, endpoint *Endpoint) error {
+	return <-setEndpoint(wallet, did, endpoint)
+}
+
+// GetEndpoint returns the endpoint of a DID stored in the wallet.
+// An error with code WalletNotFoundError is returned if no endpoint is stored for the DID.
+//
+// wallet The wallet.
+// did    The DID.
+func GetEndpoint(wallet *wallet.Wallet, did string) (endpoint *Endpoint, err error) {
+	endpointChan, errChan := getEndpoint(nil, wallet, did)
+	select {
+	case endpoint = <-endpointChan:
+	case err = <-errChan:
+		// libindy only uses the pool handle if the endpoint isn't in the wallet
+		if indyerror.Code(err) == indyerror.PoolLedgerInvalidPoolHandle {
+			err = indyerror.New(indyerror.WalletNotFoundError)
+		}
+	}
+	return
+}
+
+// ResolveEndpoint returns the endpoint of a DID. The endpoint stored in the wallet is returned
+// if there is one. Otherwise the 'endpoint' attribute (ATTRIB) of the DID is read from the ledger
+// and stored in the wallet.
+//
+// pool   The pool.
+// wallet The wallet.
+// did    The DID.
+func ResolveEndpoint(pool *pool.Pool, wallet *wallet.Wallet, did string) (endpoint *Endpoint, err error) {
+	if pool == nil {
+		return nil, fmt.Errorf("pool must be specified")
+	}
+	endpointChan, errChan := getEndpoint(pool, wallet, did)
+	select {
+	case endpoint = <-endpointChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+func setEndpoint(wallet *wallet.Wallet, did string, endpoint *Endpoint) chan error {
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return errChan
+	}
+	if endpoint == nil || endpoint.Address == "" {
+		errChan <- fmt.Errorf("endpoint address must be specified")
+		return errChan
+	}
+
+	logger.Debugf("Setting endpoint of DID [%s] - Wallet [%s], Address [%s], VerKey [%s]", did, wallet.Name, endpoint.Address, endpoint.VerKey)
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.SetEndpointForDID(walletHandle, did, endpoint.Address, endpoint.VerKey, callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
+
+// getEndpoint gets the endpoint from the wallet and, if the pool isn't nil, from the ledger
+func getEndpoint(pool *pool.Pool, wallet *wallet.Wallet, did string) (chan *Endpoint, chan error) {
+	logger.Debugf("Getting endpoint of DID [%s] - Wallet [%s], Ledger [%t]", did, wallet.Name, pool != nil)
+
+	endpointChan := make(chan *Endpoint)
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return endpointChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			sd := data.([]string)
+			endpointChan <- &Endpoint{
+				Address: sd[0],
+				VerKey:  sd[1],
+			}
+		}
+	}
+
+	poolHandle := noPoolHandle
+	if pool != nil {
+		var err error
+		poolHandle, err = pool.OpenHandle()
+		if err != nil {
+			errChan <- err
+			return endpointChan, errChan
+		}
+	}
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return endpointChan, errChan
+	}
+
+	err = indy.GetEndpointForDID(walletHandle, poolHandle, did, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return endpointChan, errChan
+}
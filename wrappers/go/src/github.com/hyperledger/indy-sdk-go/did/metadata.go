@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/common/callback"
+	"github.com/hyperledger/indy-sdk-go/indy"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+// MyDIDWithMeta is a DID owned by the caller together with its metadata.
+//
+// libindy doesn't return the key started by ReplaceKeysStart, so a key replacement in progress
+// isn't visible here; VerKey is the current key until ReplaceKeysApply is called.
+type MyDIDWithMeta struct {
+	// DID is the DID stored in the wallet
+	DID string `json:"did"`
+
+	// VerKey is the verification key of the DID
+	VerKey string `json:"verkey"`
+
+	// Metadata is the metadata stored with the DID. Empty if no metadata is stored.
+	// Metadata set with SetMetadata is json and may be decoded with UnmarshalMetadata.
+	Metadata string `json:"metadata,omitempty"`
+}
+
+// UnmarshalMetadata decodes the json metadata of the DID into the given value
+func (d *MyDIDWithMeta) UnmarshalMetadata(v interface{}) error {
+	if d.Metadata == "" {
+		return fmt.Errorf("DID [%s] has no metadata", d.DID)
+	}
+	return json.Unmarshal([]byte(d.Metadata), v)
+}
+
+// SetMetadata saves the metadata of a DID in the wallet, replacing any existing metadata.
+// The metadata is stored as json.
+//
+// wallet   The wallet.
+// did      The DID.
+// metadata The metadata. Any value that can be marshaled to json.
+func SetMetadata(wallet *wallet.Wallet, did string, metadata interface{}) error {
+	return <-setMetadata(wallet, did, metadata)
+}
+
+// GetMetadata retrieves the metadata of a DID from the wallet and decodes it into the given value.
+// An error with code WalletNotFoundError is returned if no metadata is stored for the DID.
+//
+// wallet   The wallet.
+// did      The DID.
+// metadata A pointer to the value into which the json metadata is decoded.
+func GetMetadata(wallet *wallet.Wallet, did string, metadata interface{}) error {
+	metaChan, errChan := getMetadata(wallet, did)
+	select {
+	case meta := <-metaChan:
+		if err := json.Unmarshal([]byte(meta), metadata); err != nil {
+			return fmt.Errorf("invalid metadata of DID [%s]: %s", did, err)
+		}
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// Get retrieves a DID owned by the caller, together with its metadata, from the wallet.
+//
+// wallet The wallet.
+// did    The DID.
+func Get(wallet *wallet.Wallet, did string) (didWithMeta *MyDIDWithMeta, err error) {
+	didChan, errChan := get(wallet, did)
+	select {
+	case didWithMeta = <-didChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// List retrieves all DIDs owned by the caller, together with their metadata, from the wallet.
+//
+// wallet The wallet.
+func List(wallet *wallet.Wallet) (dids []*MyDIDWithMeta, err error) {
+	didsChan, errChan := list(wallet)
+	select {
+	case dids = <-didsChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+func setMetadata(wallet *wallet.Wallet, did string, metadata interface{}) chan error {
+	logger.Debugf("Setting metadata of DID [%s] - Wallet [%s]", did, wallet.Name)
+
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return errChan
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		errChan <- fmt.Errorf("error marshalling metadata of DID [%s]: %s", did, err)
+		return errChan
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.SetDIDMetadata(walletHandle, did, string(metadataJSON), callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
+
+func getMetadata(wallet *wallet.Wallet, did string) (chan string, chan error) {
+	logger.Debugf("Getting metadata of DID [%s] - Wallet [%s]", did, wallet.Name)
+
+	metaChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return metaChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			metaChan <- data.(string)
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return metaChan, errChan
+	}
+
+	err = indy.GetDIDMetadata(walletHandle, did, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return metaChan, errChan
+}
+
+func get(wallet *wallet.Wallet, did string) (chan *MyDIDWithMeta, chan error) {
+	logger.Debugf("Getting DID [%s] - Wallet [%s]", did, wallet.Name)
+
+	didChan := make(chan *MyDIDWithMeta)
+	errChan := make(chan error, 1)
+
+	if did == "" {
+		errChan <- fmt.Errorf("DID must be specified")
+		return didChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+			return
+		}
+		didWithMeta := &MyDIDWithMeta{}
+		if err := json.Unmarshal([]byte(data.(string)), didWithMeta); err != nil {
+			errChan <- fmt.Errorf("invalid DID json: %s", err)
+			return
+		}
+		didChan <- didWithMeta
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return didChan, errChan
+	}
+
+	err = indy.GetMyDIDWithMeta(walletHandle, did, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return didChan, errChan
+}
+
+func list(wallet *wallet.Wallet) (chan []*MyDIDWithMeta, chan error) {
+	logger.Debugf("Listing DIDs - Wallet [%s]", wallet.Name)
+
+	didsChan := make(chan []*MyDIDWithMeta)
+	errChan := make(chan error, 1)
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+			return
+		}
+		dids, err := asDIDsWithMeta(data.(string))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		didsChan <- dids
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return didsChan, errChan
+	}
+
+	err = indy.ListMyDIDsWithMeta(walletHandle, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return didsChan, errChan
+}
+
+func asDIDsWithMeta(didsJSON string) ([]*MyDIDWithMeta, error) {
+	var dids []*MyDIDWithMeta
+	if err := json.Unmarshal([]byte(didsJSON), &dids); err != nil {
+		return nil, fmt.Errorf("invalid DIDs json: %s", err)
+	}
+	return dids, nil
+}
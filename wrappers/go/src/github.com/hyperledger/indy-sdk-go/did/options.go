@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+const (
+	// SeedSize is the size of a key seed in bytes
+	SeedSize = util.SeedSize
+
+	// CryptoTypeEd25519 is the ed25519 crypto type, which is the default and only supported type
+	CryptoTypeEd25519 = util.CryptoTypeEd25519
+)
+
+// CreateOptions are the options for creating a DID with CreateAndStoreMyDIDWithOptions
+type CreateOptions struct {
+	// DID is the DID to create. If empty the DID is derived from the verkey.
+	DID string
+
+	// Seed is the seed of the keys. It must be SeedSize bytes. A random seed is used if nil.
+	Seed []byte
+
+	// CryptoType is the type of the keys. Defaults to CryptoTypeEd25519.
+	CryptoType string
+
+	// CID is true if the DID is the full verkey (a cryptonym) instead of its first 16 bytes
+	CID bool
+}
+
+// Validate returns an error if the options are invalid
+func (o *CreateOptions) Validate() error {
+	if o.DID != "" {
+		if err := util.ValidateDID(o.DID); err != nil {
+			return err
+		}
+	}
+	if err := util.ValidateSeed(o.Seed); err != nil {
+		return err
+	}
+	return util.ValidateCryptoType(o.CryptoType)
+}
+
+// MarshalJSON returns the identity json expected by libindy
+func (o *CreateOptions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&identityJSON{
+		DID:        o.DID,
+		Seed:       util.EncodeSeed(o.Seed),
+		CryptoType: o.CryptoType,
+		CID:        o.CID,
+	})
+}
+
+// Validate returns an error if the options are invalid
+func (o *KeyOptions) Validate() error {
+	if err := util.ValidateSeed(o.Seed); err != nil {
+		return err
+	}
+	return util.ValidateCryptoType(o.CryptoType)
+}
+
+// MarshalJSON returns the identity json expected by libindy
+func (o *KeyOptions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&identityJSON{
+		Seed:       util.EncodeSeed(o.Seed),
+		CryptoType: o.CryptoType,
+	})
+}
+
+type identityJSON struct {
+	DID        string `json:"did,omitempty"`
+	Seed       string `json:"seed,omitempty"`
+	CryptoType string `json:"crypto_type,omitempty"`
+	CID        bool   `json:"cid,omitempty"`
+}
+
+// CreateAndStoreMyDIDWithOptions creates keys (signing and encryption keys) for a new DID
+// (owned by the caller of the library) with the given options and saves the DID with its
+// keys in the wallet. See CreateAndStoreMyDID.
+//
+// wallet  The wallet.
+// options The options of the DID. May be nil for a random DID.
+func CreateAndStoreMyDIDWithOptions(wallet *wallet.Wallet, options *CreateOptions) (*Info, error) {
+	if options == nil {
+		options = &CreateOptions{}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	didJSON, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	return CreateAndStoreMyDID(wallet, string(didJSON))
+}
+
+// NewSeed returns a random seed
+func NewSeed() ([]byte, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// DeriveSeed deterministically derives a seed from a secret and a label (HMAC-SHA256),
+// so that the keys of several DIDs can be recreated from a single secret. Different
+// labels give unrelated seeds. The secret should be at least SeedSize random bytes.
+func DeriveSeed(secret []byte, label string) ([]byte, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("secret must be specified")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(label))
+	return mac.Sum(nil), nil
+}
+
+// SeedFromString returns the seed for a string seed of SeedSize characters as used in
+// the didJSON of CreateAndStoreMyDID, e.g. "00000000000000000000000000000My1"
+func SeedFromString(seed string) ([]byte, error) {
+	if len(seed) != SeedSize {
+		return nil, fmt.Errorf("seed must be %d characters but has %d", SeedSize, len(seed))
+	}
+	return []byte(seed), nil
+}
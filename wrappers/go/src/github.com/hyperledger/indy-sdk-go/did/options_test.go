@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCreateOptions(t *testing.T) {
+	seed, err := SeedFromString(seed1)
+	if err != nil {
+		t.Fatalf("Error received from SeedFromString: %s", err)
+	}
+
+	options := &CreateOptions{DID: did1, Seed: seed, CryptoType: CryptoTypeEd25519, CID: true}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("Error received from Validate: %s", err)
+	}
+	didJSON, err := json.Marshal(options)
+	if err != nil {
+		t.Fatalf("Error marshalling options: %s", err)
+	}
+
+	var identity map[string]interface{}
+	if err := json.Unmarshal(didJSON, &identity); err != nil {
+		t.Fatalf("Error unmarshalling identity json: %s", err)
+	}
+	if identity["did"] != did1 || identity["crypto_type"] != CryptoTypeEd25519 || identity["cid"] != true {
+		t.Fatalf("Unexpected identity json: %s", didJSON)
+	}
+	// libindy decodes seeds ending with '=' as base64
+	encoded := identity["seed"].(string)
+	if !strings.HasSuffix(encoded, "=") {
+		t.Fatalf("Expecting padded base64 seed but got [%s]", encoded)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || string(decoded) != seed1 {
+		t.Fatalf("Expecting seed [%s] but got [%s]", seed1, decoded)
+	}
+
+	didJSON, err = json.Marshal(&CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error marshalling options: %s", err)
+	}
+	if string(didJSON) != "{}" {
+		t.Fatalf("Expecting empty identity json but got %s", didJSON)
+	}
+
+	if err := (&CreateOptions{Seed: []byte("short")}).Validate(); err == nil {
+		t.Fatalf("Expecting error for short seed")
+	}
+	if err := (&CreateOptions{CryptoType: "ed25591"}).Validate(); err == nil {
+		t.Fatalf("Expecting error for unsupported crypto type")
+	}
+	if err := (&CreateOptions{DID: "invalid_base58string"}).Validate(); err == nil {
+		t.Fatalf("Expecting error for invalid DID")
+	}
+	if err := (&KeyOptions{Seed: make([]byte, SeedSize+1)}).Validate(); err == nil {
+		t.Fatalf("Expecting error for long seed")
+	}
+	if _, err := SeedFromString("0000My1"); err == nil {
+		t.Fatalf("Expecting error for short string seed")
+	}
+}
+
+func TestSeeds(t *testing.T) {
+	seed, err := NewSeed()
+	if err != nil {
+		t.Fatalf("Error received from NewSeed: %s", err)
+	}
+	if len(seed) != SeedSize {
+		t.Fatalf("Expecting seed of %d bytes but got %d", SeedSize, len(seed))
+	}
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	seedA1, err := DeriveSeed(secret, "a")
+	if err != nil {
+		t.Fatalf("Error received from DeriveSeed: %s", err)
+	}
+	seedA2, _ := DeriveSeed(secret, "a")
+	seedB, _ := DeriveSeed(secret, "b")
+	if len(seedA1) != SeedSize {
+		t.Fatalf("Expecting seed of %d bytes but got %d", SeedSize, len(seedA1))
+	}
+	if !bytes.Equal(seedA1, seedA2) {
+		t.Fatalf("Expecting derived seeds for the same label to be equal")
+	}
+	if bytes.Equal(seedA1, seedB) {
+		t.Fatalf("Expecting derived seeds for different labels to differ")
+	}
+	if _, err := DeriveSeed(nil, "a"); err == nil {
+		t.Fatalf("Expecting error for missing secret")
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/ledger"
+	"github.com/hyperledger/indy-sdk-go/pool"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+const (
+	// DocumentContext is the json-ld context of DID documents
+	DocumentContext = "https://www.w3.org/ns/did/v1"
+
+	// Ed25519VerificationKey2018 is the type of the verification method of a DID
+	Ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+
+	// IndyAgentService is the type of the service of a DID with an endpoint
+	IndyAgentService = "IndyAgent"
+
+	endpointAttrib = "endpoint"
+)
+
+// Document is a W3C DID document
+type Document struct {
+	Context            string                `json:"@context"`
+	ID                 string                `json:"id"`
+	VerificationMethod []*VerificationMethod `json:"verificationMethod"`
+	Authentication     []string              `json:"authentication"`
+	Service            []*Service            `json:"service,omitempty"`
+}
+
+// VerificationMethod is a key of a DID document
+type VerificationMethod struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	Controller      string `json:"controller"`
+	PublicKeyBase58 string `json:"publicKeyBase58"`
+}
+
+// Service is a service endpoint of a DID document
+type Service struct {
+	ID              string   `json:"id"`
+	Type            string   `json:"type"`
+	ServiceEndpoint string   `json:"serviceEndpoint"`
+	RecipientKeys   []string `json:"recipientKeys,omitempty"`
+}
+
+// NewDocument assembles the DID document of a DID with the given verkey and endpoint.
+// The verkey may be abbreviated. The document has no service if the endpoint is nil.
+func NewDocument(did, verKey string, endpoint *Endpoint) (*Document, error) {
+	id, err := util.QualifyDID(did)
+	if err != nil {
+		return nil, err
+	}
+	unqualified, _ := util.UnqualifyDID(id)
+	fullVerKey, err := util.ExpandVerKey(unqualified, verKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := id + "#key-1"
+	doc := &Document{
+		Context: DocumentContext,
+		ID:      id,
+		VerificationMethod: []*VerificationMethod{{
+			ID:              keyID,
+			Type:            Ed25519VerificationKey2018,
+			Controller:      id,
+			PublicKeyBase58: fullVerKey,
+		}},
+		Authentication: []string{keyID},
+	}
+
+	if endpoint != nil && endpoint.Address != "" {
+		recipientKey := endpoint.VerKey
+		if recipientKey == "" {
+			recipientKey = fullVerKey
+		}
+		doc.Service = []*Service{{
+			ID:              id + "#indy",
+			Type:            IndyAgentService,
+			ServiceEndpoint: endpoint.Address,
+			RecipientKeys:   []string{recipientKey},
+		}}
+	}
+	return doc, nil
+}
+
+// JSON returns the json of the DID document
+func (d *Document) JSON() (string, error) {
+	bytes, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// ResolverOptions are the options of a Resolver created with NewResolverWithOptions
+type ResolverOptions struct {
+	// EndpointMaxAge is the maximum age of a cached endpoint. An endpoint that wasn't read from
+	// the ledger by the resolver within the maximum age is read from the ledger again, so the
+	// first resolution of a DID by a new resolver always reads the ledger. With a maximum age
+	// the ledger takes precedence over endpoints stored in the wallet. Zero means that cached
+	// endpoints don't expire.
+	EndpointMaxAge time.Duration
+}
+
+// Resolver resolves DIDs to DID documents from the ledger. The results are cached in the wallet.
+type Resolver struct {
+	pool    *pool.Pool
+	wallet  *wallet.Wallet
+	maxAge  time.Duration
+	mutex   sync.Mutex
+	fetched map[string]time.Time
+}
+
+// NewResolver returns a DID resolver that reads the ledger of the given pool and caches in the given wallet
+func NewResolver(pool *pool.Pool, wallet *wallet.Wallet) *Resolver {
+	return NewResolverWithOptions(pool, wallet, nil)
+}
+
+// NewResolverWithOptions returns a DID resolver like NewResolver with the given options. The options are optional.
+func NewResolverWithOptions(pool *pool.Pool, wallet *wallet.Wallet, opts *ResolverOptions) *Resolver {
+	r := &Resolver{pool: pool, wallet: wallet, fetched: make(map[string]time.Time)}
+	if opts != nil {
+		r.maxAge = opts.EndpointMaxAge
+	}
+	return r
+}
+
+// Resolve returns the DID document of a DID, which may be qualified ('did:sov:' prefix).
+//
+// The verkey is read from the NYM on the ledger with KeyForDID, so it's cached in the wallet
+// and refreshed according to the freshness time of the wallet (see wallet.RuntimeConfig).
+// The endpoint is read from the 'endpoint' attribute (ATTRIB) on the ledger unless an endpoint
+// is stored in the wallet, and cached in the wallet. A cached endpoint is read from the ledger
+// again once it's older than the maximum age of the resolver (see ResolverOptions). Use Refresh
+// to read the endpoint from the ledger regardless of the cache.
+func (r *Resolver) Resolve(did string) (*Document, error) {
+	return r.resolve(did, false)
+}
+
+// Refresh returns the DID document of a DID like Resolve but always reads the endpoint from
+// the ledger and updates the endpoint cached in the wallet.
+func (r *Resolver) Refresh(did string) (*Document, error) {
+	return r.resolve(did, true)
+}
+
+func (r *Resolver) resolve(did string, refresh bool) (*Document, error) {
+	unqualified, err := util.UnqualifyDID(did)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Resolving DID [%s] - Pool [%s], Wallet [%s], Refresh: %t", unqualified, r.pool.Name, r.wallet.Name, refresh)
+
+	verKey, err := KeyForDID(r.pool, r.wallet, unqualified)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving verkey of DID [%s]: %s", unqualified, err)
+	}
+
+	endpoint, err := r.endpoint(unqualified, refresh)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving endpoint of DID [%s]: %s", unqualified, err)
+	}
+
+	return NewDocument(unqualified, verKey, endpoint)
+}
+
+// endpoint returns the endpoint of the DID or nil if the DID has no endpoint
+func (r *Resolver) endpoint(did string, refresh bool) (*Endpoint, error) {
+	if !refresh && r.fresh(did, time.Now()) {
+		endpoint, err := GetEndpoint(r.wallet, did)
+		if err == nil {
+			return endpoint, nil
+		}
+		if indyerror.Code(err) != indyerror.WalletNotFoundError {
+			return nil, err
+		}
+	}
+
+	fetched := time.Now()
+	attrib, err := ledger.GetRawAttrib(r.pool, did, did, endpointAttrib)
+	if err == ledger.ErrAttribNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := parseEndpointAttrib(attrib)
+	if err != nil || endpoint == nil {
+		return nil, err
+	}
+
+	if err := SetEndpoint(r.wallet, did, endpoint); err != nil {
+		logger.Warnf("Error caching endpoint of DID [%s]: %s", did, err)
+	} else {
+		r.setFetched(did, fetched)
+	}
+	return endpoint, nil
+}
+
+// fresh returns true if the endpoint of the DID cached in the wallet may be used at the given time
+func (r *Resolver) fresh(did string, now time.Time) bool {
+	if r.maxAge <= 0 {
+		return true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	fetched, ok := r.fetched[did]
+	return ok && now.Sub(fetched) <= r.maxAge
+}
+
+func (r *Resolver) setFetched(did string, fetched time.Time) {
+	if r.maxAge <= 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.fetched[did] = fetched
+}
+
+// parseEndpointAttrib returns the endpoint of an 'endpoint' attribute or nil if it has no address
+func parseEndpointAttrib(attrib string) (*Endpoint, error) {
+	var data struct {
+		Endpoint *Endpoint `json:"endpoint"`
+	}
+	if err := json.Unmarshal([]byte(attrib), &data); err != nil {
+		return nil, fmt.Errorf("invalid endpoint attribute: %s", err)
+	}
+	if data.Endpoint == nil || data.Endpoint.Address == "" {
+		return nil, nil
+	}
+	return data.Endpoint, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const (
+	// DID and verkey for seed 00000000000000000000000000000My1
+	resolvedDID     = "VsKV7grR1BUE29mG2Fm2kX"
+	resolvedVerKey  = "GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa"
+	abbreviatedKey  = "~HYwqs2vrTc8Tn4uBV7NBTe"
+	transportVerKey = "CnEDk9HrMnmiHXEV1WFgbVCRteYnPqsJwrTdcZaNhFVW"
+)
+
+func TestNewDocument(t *testing.T) {
+	doc, err := NewDocument(resolvedDID, abbreviatedKey, &Endpoint{Address: "127.0.0.1:9700", VerKey: transportVerKey})
+	if err != nil {
+		t.Fatalf("Error received from NewDocument: %s", err)
+	}
+	if doc.ID != "did:sov:"+resolvedDID {
+		t.Fatalf("Unexpected ID [%s]", doc.ID)
+	}
+	if len(doc.VerificationMethod) != 1 || doc.VerificationMethod[0].PublicKeyBase58 != resolvedVerKey {
+		t.Fatalf("Expecting verification method with full verkey [%s]", resolvedVerKey)
+	}
+	if len(doc.Authentication) != 1 || doc.Authentication[0] != doc.VerificationMethod[0].ID {
+		t.Fatalf("Expecting authentication to reference the verification method")
+	}
+	if len(doc.Service) != 1 {
+		t.Fatalf("Expecting one service but got %d", len(doc.Service))
+	}
+	service := doc.Service[0]
+	if service.Type != IndyAgentService || service.ServiceEndpoint != "127.0.0.1:9700" || service.RecipientKeys[0] != transportVerKey {
+		t.Fatalf("Unexpected service %+v", service)
+	}
+
+	docJSON, err := doc.JSON()
+	if err != nil {
+		t.Fatalf("Error received from JSON: %s", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(docJSON), &parsed); err != nil {
+		t.Fatalf("Error unmarshalling DID document: %s", err)
+	}
+	if parsed["@context"] != DocumentContext {
+		t.Fatalf("Unexpected context in %s", docJSON)
+	}
+
+	// Without endpoint
+	doc, err = NewDocument("did:sov:"+resolvedDID, resolvedVerKey, nil)
+	if err != nil {
+		t.Fatalf("Error received from NewDocument: %s", err)
+	}
+	if len(doc.Service) != 0 {
+		t.Fatalf("Expecting no service but got %d", len(doc.Service))
+	}
+
+	if _, err := NewDocument("invalid_base58string", resolvedVerKey, nil); err == nil {
+		t.Fatalf("Expecting error for invalid DID")
+	}
+}
+
+func TestParseEndpointAttrib(t *testing.T) {
+	endpoint, err := parseEndpointAttrib(`{"endpoint":{"ha":"127.0.0.1:9700","verkey":"` + transportVerKey + `"}}`)
+	if err != nil {
+		t.Fatalf("Error received from parseEndpointAttrib: %s", err)
+	}
+	if endpoint.Address != "127.0.0.1:9700" || endpoint.VerKey != transportVerKey {
+		t.Fatalf("Unexpected endpoint %+v", endpoint)
+	}
+
+	endpoint, err = parseEndpointAttrib(`{"url":"https://example.com"}`)
+	if err != nil || endpoint != nil {
+		t.Fatalf("Expecting no endpoint but got [%v], error [%v]", endpoint, err)
+	}
+
+	if _, err := parseEndpointAttrib(`{`); err == nil {
+		t.Fatalf("Expecting error for invalid attribute")
+	}
+}
+
+func TestEndpointFreshness(t *testing.T) {
+	now := time.Now()
+
+	r := NewResolver(nil, nil)
+	if !r.fresh(resolvedDID, now) {
+		t.Fatalf("Expecting cached endpoints not to expire without a maximum age")
+	}
+
+	r = NewResolverWithOptions(nil, nil, &ResolverOptions{EndpointMaxAge: time.Minute})
+	if r.fresh(resolvedDID, now) {
+		t.Fatalf("Expecting endpoint that wasn't read by the resolver not to be fresh")
+	}
+	r.setFetched(resolvedDID, now)
+	if !r.fresh(resolvedDID, now.Add(time.Minute)) {
+		t.Fatalf("Expecting endpoint to be fresh within the maximum age")
+	}
+	if r.fresh(resolvedDID, now.Add(time.Minute+time.Second)) {
+		t.Fatalf("Expecting endpoint to expire after the maximum age")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/did/util"
+	"github.com/hyperledger/indy-sdk-go/ledger"
+	"github.com/hyperledger/indy-sdk-go/pool"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+// RotationError is returned by RotateKey if the new key may have been written to the
+// ledger but wasn't applied in the wallet, so that the ledger and the wallet may disagree
+// on the key of the DID. The new key is kept in the wallet as the pending key of the DID;
+// CompleteRotation must be called, once the ledger is reachable, to apply it in the wallet
+// if the ledger has it.
+type RotationError struct {
+	// DID is the DID whose key was rotated
+	DID string
+
+	// NewVerKey is the new verkey of the DID
+	NewVerKey string
+
+	// Err is the cause of the error
+	Err error
+}
+
+// The wallet and ledger operations of a rotation (replaced in unit tests)
+var (
+	startKeyReplacement = ReplaceKeysStart
+	applyKeyReplacement = ReplaceKeysApply
+	buildNYMRequest     = ledger.BuildNYMRequest
+	submitRequest       = ledger.SignAndSubmitRequest
+	getNYM              = ledger.GetNYM
+)
+
+func (e *RotationError) Error() string {
+	return fmt.Sprintf("key rotation of DID [%s] is incomplete - new verkey [%s] may be on the ledger but isn't applied in the wallet: %s", e.DID, e.NewVerKey, e.Err)
+}
+
+// RotateKey replaces the keys of a DID owned by the caller and returns the new verkey.
+//
+// New keys are generated in the wallet and a NYM with the new verkey, signed by the
+// current key, is written to the ledger. The new keys are applied in the wallet only
+// after the ledger has accepted the NYM. If the ledger rejects the NYM the current key
+// remains in use. (The unused new keys stay in the wallet until the next rotation
+// replaces them.)
+//
+// A *RotationError is returned if the ledger may have the new key but it isn't applied
+// in the wallet: if the outcome of the submission is unknown (e.g. after a timeout) and
+// the ledger doesn't have the new key yet, since the NYM may still be ordered later, or
+// if the new key couldn't be applied in the wallet. The rotation must then be completed
+// with CompleteRotation before the DID is used again, since requests signed with the
+// current key are rejected if the ledger has the new key.
+//
+// pool   The pool.
+// wallet The wallet.
+// did    The DID stored in the wallet.
+func RotateKey(pool *pool.Pool, wallet *wallet.Wallet, did string) (string, error) {
+	if did == "" {
+		return "", fmt.Errorf("DID must be specified")
+	}
+
+	newVerKey, err := startKeyReplacement(wallet, did, nil)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := buildNYMRequest(did, did, newVerKey, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// The request is signed with the current key since the new key isn't applied yet
+	response, err := submitRequest(pool, wallet, did, request)
+	if err == nil {
+		err = ledger.CheckReply("NYM", response)
+	}
+	if _, ok := err.(*ledger.RejectedError); ok {
+		logger.Warnf("Key rotation of DID [%s] rejected by the ledger: %s", did, err)
+		return "", err
+	}
+	if err != nil {
+		// The outcome is unknown so check whether the ledger already has the new key. If it
+		// doesn't, the NYM may still be ordered later so only CompleteRotation can decide.
+		logger.Warnf("Error submitting NYM for key rotation of DID [%s]: %s", did, err)
+		if confirmErr := confirmVerKey(pool, did, newVerKey); confirmErr != nil {
+			logger.Debugf("New key of DID [%s] isn't confirmed by the ledger: %s", did, confirmErr)
+			return "", &RotationError{DID: did, NewVerKey: newVerKey, Err: err}
+		}
+	}
+
+	if err := applyKeyReplacement(wallet, did); err != nil {
+		return "", &RotationError{DID: did, NewVerKey: newVerKey, Err: err}
+	}
+
+	logger.Infof("Rotated key of DID [%s]", did)
+	return newVerKey, nil
+}
+
+// CompleteRotation completes a key rotation that failed with a *RotationError. If the
+// ledger has the new key of the rotation it's applied in the wallet and true is returned.
+// If the ledger has another key, the NYM of the rotation wasn't written, the current key
+// remains in use and false is returned; RotateKey may then be called again. If the ledger
+// can't be queried an error is returned and CompleteRotation may be retried.
+//
+// pool        The pool.
+// wallet      The wallet.
+// rotationErr The error returned by RotateKey.
+func CompleteRotation(pool *pool.Pool, wallet *wallet.Wallet, rotationErr *RotationError) (bool, error) {
+	if rotationErr == nil {
+		return false, fmt.Errorf("rotation error must be specified")
+	}
+
+	err := confirmVerKey(pool, rotationErr.DID, rotationErr.NewVerKey)
+	if _, ok := err.(*verKeyMismatchError); ok {
+		logger.Infof("Key rotation of DID [%s] didn't take effect on the ledger: %s", rotationErr.DID, err)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := applyKeyReplacement(wallet, rotationErr.DID); err != nil {
+		return false, err
+	}
+
+	logger.Infof("Completed key rotation of DID [%s]", rotationErr.DID)
+	return true, nil
+}
+
+type verKeyMismatchError struct {
+	verKey string
+}
+
+func (e *verKeyMismatchError) Error() string {
+	return fmt.Sprintf("ledger has verkey [%s]", e.verKey)
+}
+
+// confirmVerKey returns nil if the ledger has the given verkey for the DID and
+// a *verKeyMismatchError if it has another verkey
+func confirmVerKey(pool *pool.Pool, did, verKey string) error {
+	nym, err := getNYM(pool, did, did)
+	if err != nil {
+		return err
+	}
+	// The ledger may return the verkey abbreviated
+	ledgerVerKey, err := util.ExpandVerKey(did, nym.VerKey)
+	if err != nil {
+		return err
+	}
+	if ledgerVerKey != verKey {
+		return &verKeyMismatchError{verKey: nym.VerKey}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package did
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/role"
+	"github.com/hyperledger/indy-sdk-go/common/types"
+	"github.com/hyperledger/indy-sdk-go/ledger"
+	"github.com/hyperledger/indy-sdk-go/pool"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+const (
+	rotationVerKey    = "GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa"
+	rotationNewVerKey = "FYmoFw55GeQH7SRFa37dkx1d2dZ3zUF8ckg7wmL7ofN4"
+
+	replyResponse  = `{"op":"REPLY","result":{}}`
+	rejectResponse = `{"op":"REJECT","reason":"client request invalid"}`
+)
+
+// rotationLedger simulates the wallet and the ledger of a key rotation
+type rotationLedger struct {
+	submitResponse string
+	submitErr      error
+	ledgerVerKey   string
+	getNYMErr      error
+	applyErr       error
+	applied        bool
+}
+
+// stub replaces the operations of a rotation with the simulated ones and
+// returns a function that restores them
+func (l *rotationLedger) stub() func() {
+	start, apply, build, submit, get := startKeyReplacement, applyKeyReplacement, buildNYMRequest, submitRequest, getNYM
+
+	startKeyReplacement = func(*wallet.Wallet, string, *KeyOptions) (string, error) {
+		return rotationNewVerKey, nil
+	}
+	applyKeyReplacement = func(*wallet.Wallet, string) error {
+		if l.applyErr != nil {
+			return l.applyErr
+		}
+		l.applied = true
+		return nil
+	}
+	buildNYMRequest = func(string, string, string, *types.Alias, *role.Role) (string, error) {
+		return "{}", nil
+	}
+	submitRequest = func(*pool.Pool, *wallet.Wallet, string, string) (string, error) {
+		return l.submitResponse, l.submitErr
+	}
+	getNYM = func(*pool.Pool, string, string) (*ledger.NYM, error) {
+		if l.getNYMErr != nil {
+			return nil, l.getNYMErr
+		}
+		return &ledger.NYM{DID: did1, VerKey: l.ledgerVerKey}, nil
+	}
+
+	return func() {
+		startKeyReplacement, applyKeyReplacement, buildNYMRequest, submitRequest, getNYM = start, apply, build, submit, get
+	}
+}
+
+func TestRotateKey(t *testing.T) {
+	timeoutErr := fmt.Errorf("timeout")
+
+	tests := []struct {
+		name          string
+		ledger        rotationLedger
+		expectApplied bool
+		expectErr     bool
+		expectRotErr  bool
+	}{
+		{
+			name:          "accepted",
+			ledger:        rotationLedger{submitResponse: replyResponse},
+			expectApplied: true,
+		},
+		{
+			name:      "rejected",
+			ledger:    rotationLedger{submitResponse: rejectResponse},
+			expectErr: true,
+		},
+		{
+			name:          "unknown outcome with new key on the ledger",
+			ledger:        rotationLedger{submitErr: timeoutErr, ledgerVerKey: rotationNewVerKey},
+			expectApplied: true,
+		},
+		{
+			name:         "unknown outcome with current key on the ledger",
+			ledger:       rotationLedger{submitErr: timeoutErr, ledgerVerKey: rotationVerKey},
+			expectErr:    true,
+			expectRotErr: true,
+		},
+		{
+			name:         "unknown outcome with unreachable ledger",
+			ledger:       rotationLedger{submitErr: timeoutErr, getNYMErr: timeoutErr},
+			expectErr:    true,
+			expectRotErr: true,
+		},
+		{
+			name:         "apply failure",
+			ledger:       rotationLedger{submitResponse: replyResponse, applyErr: fmt.Errorf("wallet closed")},
+			expectErr:    true,
+			expectRotErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		l := test.ledger
+		restore := l.stub()
+		verKey, err := RotateKey(nil, nil, did1)
+		restore()
+
+		if test.expectErr && err == nil {
+			t.Fatalf("%s: Expecting error but got success", test.name)
+		}
+		if !test.expectErr && err != nil {
+			t.Fatalf("%s: Error received from RotateKey: %s", test.name, err)
+		}
+		if !test.expectErr && verKey != rotationNewVerKey {
+			t.Fatalf("%s: Expecting verkey [%s] but got [%s]", test.name, rotationNewVerKey, verKey)
+		}
+		if rotErr, ok := err.(*RotationError); ok != test.expectRotErr {
+			t.Fatalf("%s: Expecting RotationError [%t] but got [%v]", test.name, test.expectRotErr, err)
+		} else if ok && rotErr.NewVerKey != rotationNewVerKey {
+			t.Fatalf("%s: Expecting new verkey [%s] in RotationError but got [%s]", test.name, rotationNewVerKey, rotErr.NewVerKey)
+		}
+		if l.applied != test.expectApplied {
+			t.Fatalf("%s: Expecting new key applied [%t] but got [%t]", test.name, test.expectApplied, l.applied)
+		}
+	}
+}
+
+func TestCompleteRotation(t *testing.T) {
+	if _, err := CompleteRotation(nil, nil, nil); err == nil {
+		t.Fatalf("Expecting error for missing rotation error")
+	}
+
+	rotationErr := &RotationError{DID: did1, NewVerKey: rotationNewVerKey, Err: fmt.Errorf("timeout")}
+
+	tests := []struct {
+		name           string
+		ledger         rotationLedger
+		expectComplete bool
+		expectErr      bool
+	}{
+		{
+			name:           "new key on the ledger",
+			ledger:         rotationLedger{ledgerVerKey: rotationNewVerKey},
+			expectComplete: true,
+		},
+		{
+			name:   "current key on the ledger",
+			ledger: rotationLedger{ledgerVerKey: rotationVerKey},
+		},
+		{
+			name:      "unreachable ledger",
+			ledger:    rotationLedger{getNYMErr: fmt.Errorf("timeout")},
+			expectErr: true,
+		},
+		{
+			name:      "apply failure",
+			ledger:    rotationLedger{ledgerVerKey: rotationNewVerKey, applyErr: fmt.Errorf("wallet closed")},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		l := test.ledger
+		restore := l.stub()
+		complete, err := CompleteRotation(nil, nil, rotationErr)
+		restore()
+
+		if test.expectErr && err == nil {
+			t.Fatalf("%s: Expecting error but got success", test.name)
+		}
+		if !test.expectErr && err != nil {
+			t.Fatalf("%s: Error received from CompleteRotation: %s", test.name, err)
+		}
+		if complete != test.expectComplete {
+			t.Fatalf("%s: Expecting complete [%t] but got [%t]", test.name, test.expectComplete, complete)
+		}
+		if l.applied != test.expectComplete {
+			t.Fatalf("%s: Expecting new key applied [%t] but got [%t]", test.name, test.expectComplete, l.applied)
+		}
+	}
+}
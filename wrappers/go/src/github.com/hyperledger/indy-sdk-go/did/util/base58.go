@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"fmt"
+)
+
+// alphabet is the Bitcoin base58 alphabet used by Indy
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var decodeMap [256]int8
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = int8(i)
+	}
+}
+
+// EncodeBase58 encodes the bytes as base58
+func EncodeBase58(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256)/log(58) ~ 1.37
+	digits := make([]byte, 0, len(b)*138/100+1)
+	for _, c := range b[zeros:] {
+		carry := int(c)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = alphabet[d]
+	}
+	return string(out)
+}
+
+// DecodeBase58 decodes a base58 string
+func DecodeBase58(s string) ([]byte, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty base58 string")
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// log(58)/log(256) ~ 0.74
+	digits := make([]byte, 0, len(s)*74/100+1)
+	for i := zeros; i < len(s); i++ {
+		v := decodeMap[s[i]]
+		if v < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q at position %d", s[i], i)
+		}
+		carry := int(v)
+		for j := range digits {
+			carry += int(digits[j]) * 58
+			digits[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i, d := range digits {
+		out[len(out)-1-i] = d
+	}
+	return out, nil
+}
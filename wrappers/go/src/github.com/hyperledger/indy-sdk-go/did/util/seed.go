@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// ValidateSeed returns an error unless the seed is nil (a random seed) or SeedSize bytes
+func ValidateSeed(seed []byte) error {
+	if seed != nil && len(seed) != SeedSize {
+		return fmt.Errorf("seed must be %d bytes but has %d", SeedSize, len(seed))
+	}
+	return nil
+}
+
+// ValidateCryptoType returns an error unless the crypto type is empty (the default) or CryptoTypeEd25519
+func ValidateCryptoType(cryptoType string) error {
+	if cryptoType != "" && cryptoType != CryptoTypeEd25519 {
+		return fmt.Errorf("unsupported crypto type [%s]", cryptoType)
+	}
+	return nil
+}
+
+// EncodeSeed encodes the seed for the key json of libindy. An empty string is returned for
+// a nil seed. The seed is encoded as padded base64, which libindy decodes since it ends with '='
+// (a 32 byte seed always has one padding character).
+func EncodeSeed(seed []byte) string {
+	if seed == nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(seed)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DIDSize is the size in bytes of a DID derived from the first half of its verkey
+	DIDSize = 16
+
+	// CIDSize is the size in bytes of a cryptonym, i.e. a DID that is the full verkey
+	CIDSize = 32
+
+	// VerKeySize is the size in bytes of a full verkey
+	VerKeySize = 32
+
+	// AbbreviatedPrefix is the prefix of abbreviated verkeys
+	AbbreviatedPrefix = "~"
+
+	// SovPrefix is the prefix of fully qualified DIDs of the Sovrin DID method
+	SovPrefix = "did:sov:"
+
+	// SeedSize is the size of a key seed in bytes
+	SeedSize = 32
+
+	// CryptoTypeEd25519 is the ed25519 crypto type, which is the default and only supported type
+	CryptoTypeEd25519 = "ed25519"
+)
+
+// ValidateDID returns an error unless the DID is a base58 encoded 16 or 32 byte
+// value, which is what libindy accepts. Qualified DIDs (see UnqualifyDID) aren't accepted.
+func ValidateDID(did string) error {
+	_, err := decodeDID(did)
+	return err
+}
+
+// ValidateVerKey returns an error unless the verkey is either a full verkey (base58 encoded
+// 32 bytes) or an abbreviated verkey ('~' followed by base58 encoded 16 bytes). A full verkey
+// may have the crypto type as suffix, e.g. ':ed25519'.
+func ValidateVerKey(verKey string) error {
+	if IsAbbreviated(verKey) {
+		_, err := decodeAbbreviated(verKey)
+		return err
+	}
+	_, err := decodeVerKey(verKey)
+	return err
+}
+
+// IsAbbreviated returns true if the verkey is abbreviated
+func IsAbbreviated(verKey string) bool {
+	return strings.HasPrefix(verKey, AbbreviatedPrefix)
+}
+
+// AbbreviateVerKey returns the abbreviated verkey of the DID if the DID is the first 16 bytes
+// of the verkey, otherwise the full verkey is returned. This is the same as indy_abbreviate_verkey.
+func AbbreviateVerKey(did, verKey string) (string, error) {
+	didBytes, err := decodeDID(did)
+	if err != nil {
+		return "", err
+	}
+	if IsAbbreviated(verKey) {
+		if _, err := decodeAbbreviated(verKey); err != nil {
+			return "", err
+		}
+		return verKey, nil
+	}
+	key, err := decodeVerKey(verKey)
+	if err != nil {
+		return "", err
+	}
+
+	if !bytes.Equal(key[:DIDSize], didBytes) {
+		return verKey, nil
+	}
+	return AbbreviatedPrefix + EncodeBase58(key[DIDSize:]), nil
+}
+
+// ExpandVerKey returns the full verkey of the DID for an abbreviated verkey.
+// A full verkey is returned unchanged.
+func ExpandVerKey(did, verKey string) (string, error) {
+	didBytes, err := decodeDID(did)
+	if err != nil {
+		return "", err
+	}
+	if !IsAbbreviated(verKey) {
+		if _, err := decodeVerKey(verKey); err != nil {
+			return "", err
+		}
+		return verKey, nil
+	}
+	if len(didBytes) != DIDSize {
+		return "", fmt.Errorf("abbreviated verkey can't be used with %d byte DID [%s]", len(didBytes), did)
+	}
+	suffix, err := decodeAbbreviated(verKey)
+	if err != nil {
+		return "", err
+	}
+	return EncodeBase58(append(didBytes, suffix...)), nil
+}
+
+// IsQualified returns true if the DID is a fully qualified DID of any method, e.g. 'did:sov:...'
+func IsQualified(did string) bool {
+	return strings.HasPrefix(did, "did:")
+}
+
+// QualifyDID returns the fully qualified Sovrin DID ('did:sov:' prefix) for the DID.
+// A qualified Sovrin DID is validated and returned unchanged.
+func QualifyDID(did string) (string, error) {
+	unqualified, err := UnqualifyDID(did)
+	if err != nil {
+		return "", err
+	}
+	return SovPrefix + unqualified, nil
+}
+
+// UnqualifyDID returns the DID without the 'did:sov:' prefix as accepted by libindy.
+// A DID without prefix is validated and returned unchanged. An error is returned for
+// qualified DIDs of other methods.
+func UnqualifyDID(did string) (string, error) {
+	unqualified := did
+	if IsQualified(did) {
+		if !strings.HasPrefix(did, SovPrefix) {
+			return "", fmt.Errorf("unsupported DID method of [%s]", did)
+		}
+		unqualified = did[len(SovPrefix):]
+	}
+	if err := ValidateDID(unqualified); err != nil {
+		return "", err
+	}
+	return unqualified, nil
+}
+
+func decodeDID(did string) ([]byte, error) {
+	if did == "" {
+		return nil, fmt.Errorf("DID must be specified")
+	}
+	b, err := DecodeBase58(did)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DID [%s]: %s", did, err)
+	}
+	if len(b) != DIDSize && len(b) != CIDSize {
+		return nil, fmt.Errorf("invalid DID [%s]: unexpected length %d", did, len(b))
+	}
+	return b, nil
+}
+
+func decodeVerKey(verKey string) ([]byte, error) {
+	if verKey == "" {
+		return nil, fmt.Errorf("verkey must be specified")
+	}
+	key := verKey
+	if i := strings.Index(verKey, ":"); i >= 0 {
+		if verKey[i+1:] != CryptoTypeEd25519 {
+			return nil, fmt.Errorf("unsupported crypto type of verkey [%s]", verKey)
+		}
+		key = verKey[:i]
+	}
+	b, err := DecodeBase58(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verkey [%s]: %s", verKey, err)
+	}
+	if len(b) != VerKeySize {
+		return nil, fmt.Errorf("invalid verkey [%s]: unexpected length %d", verKey, len(b))
+	}
+	return b, nil
+}
+
+func decodeAbbreviated(verKey string) ([]byte, error) {
+	b, err := DecodeBase58(verKey[len(AbbreviatedPrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid verkey [%s]: %s", verKey, err)
+	}
+	if len(b) != VerKeySize-DIDSize {
+		return nil, fmt.Errorf("invalid abbreviated verkey [%s]: unexpected length %d", verKey, len(b))
+	}
+	return b, nil
+}
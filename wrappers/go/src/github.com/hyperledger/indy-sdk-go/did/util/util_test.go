@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+const (
+	// DID and verkey for seed 00000000000000000000000000000My1
+	did1         = "VsKV7grR1BUE29mG2Fm2kX"
+	verKey1      = "GjZWsBLgZCR18aL468JAT7w9CZRiBnpxUPPgyQxh4voa"
+	abbrVerKey1  = "~HYwqs2vrTc8Tn4uBV7NBTe"
+	trusteeDID   = "V4SGRU86Z58d6TV7PBUe6f"
+	cid          = "CnEDk9HrMnmiHXEV1WFgbVCRteYnPqsJwrTdcZaNhFVW"
+	invalidDID   = "invalid_base58string"
+	shortVerKey  = "invalidVerkeyLength"
+	invalidChars = "CnEDk___MnmiHXEV1WFgbV___eYnPqs___TdcZaNhFVW"
+)
+
+func TestBase58(t *testing.T) {
+	for _, b := range [][]byte{{}, {0}, {0, 0, 1}, {1, 2, 3, 4, 5}, {0xff, 0xff, 0xff, 0xff}} {
+		encoded := EncodeBase58(b)
+		if len(b) == 0 {
+			if encoded != "" {
+				t.Fatalf("Expecting empty string but got [%s]", encoded)
+			}
+			continue
+		}
+		decoded, err := DecodeBase58(encoded)
+		if err != nil {
+			t.Fatalf("Error received from DecodeBase58: %s", err)
+		}
+		if !bytes.Equal(decoded, b) {
+			t.Fatalf("Expecting %v but got %v", b, decoded)
+		}
+	}
+
+	if s := EncodeBase58([]byte("Hello World!")); s != "2NEpo7TZRRrLZSi2U" {
+		t.Fatalf("Unexpected encoding [%s]", s)
+	}
+	if _, err := DecodeBase58("0OIl"); err == nil {
+		t.Fatalf("Expecting error for characters outside the alphabet")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, did := range []string{did1, trusteeDID, cid} {
+		if err := ValidateDID(did); err != nil {
+			t.Fatalf("Error received from ValidateDID for [%s]: %s", did, err)
+		}
+	}
+	for _, did := range []string{"", invalidDID, verKey1[:10], SovPrefix + did1} {
+		if err := ValidateDID(did); err == nil {
+			t.Fatalf("Expecting error for DID [%s]", did)
+		}
+	}
+
+	for _, verKey := range []string{verKey1, abbrVerKey1, verKey1 + ":ed25519"} {
+		if err := ValidateVerKey(verKey); err != nil {
+			t.Fatalf("Error received from ValidateVerKey for [%s]: %s", verKey, err)
+		}
+	}
+	for _, verKey := range []string{"", shortVerKey, invalidChars, "~" + verKey1, verKey1 + ":secp256k1"} {
+		if err := ValidateVerKey(verKey); err == nil {
+			t.Fatalf("Expecting error for verkey [%s]", verKey)
+		}
+	}
+}
+
+func TestAbbreviateVerKey(t *testing.T) {
+	abbr, err := AbbreviateVerKey(did1, verKey1)
+	if err != nil {
+		t.Fatalf("Error received from AbbreviateVerKey: %s", err)
+	}
+	if abbr != abbrVerKey1 {
+		t.Fatalf("Expecting [%s] but got [%s]", abbrVerKey1, abbr)
+	}
+
+	// The verkey isn't abbreviated if the DID isn't derived from it
+	full, err := AbbreviateVerKey(trusteeDID, verKey1)
+	if err != nil {
+		t.Fatalf("Error received from AbbreviateVerKey: %s", err)
+	}
+	if full != verKey1 {
+		t.Fatalf("Expecting [%s] but got [%s]", verKey1, full)
+	}
+
+	expanded, err := ExpandVerKey(did1, abbrVerKey1)
+	if err != nil {
+		t.Fatalf("Error received from ExpandVerKey: %s", err)
+	}
+	if expanded != verKey1 {
+		t.Fatalf("Expecting [%s] but got [%s]", verKey1, expanded)
+	}
+	if expanded, _ := ExpandVerKey(did1, verKey1); expanded != verKey1 {
+		t.Fatalf("Expecting full verkey to be unchanged but got [%s]", expanded)
+	}
+	if _, err := ExpandVerKey(cid, abbrVerKey1); err == nil {
+		t.Fatalf("Expecting error for abbreviated verkey of cryptonym")
+	}
+	if _, err := AbbreviateVerKey(invalidDID, verKey1); err == nil {
+		t.Fatalf("Expecting error for invalid DID")
+	}
+}
+
+func TestQualifiedDID(t *testing.T) {
+	qualified, err := QualifyDID(did1)
+	if err != nil {
+		t.Fatalf("Error received from QualifyDID: %s", err)
+	}
+	if qualified != SovPrefix+did1 {
+		t.Fatalf("Expecting [%s] but got [%s]", SovPrefix+did1, qualified)
+	}
+	if !IsQualified(qualified) || IsQualified(did1) {
+		t.Fatalf("Unexpected result from IsQualified")
+	}
+	if q, _ := QualifyDID(qualified); q != qualified {
+		t.Fatalf("Expecting qualified DID to be unchanged but got [%s]", q)
+	}
+
+	for _, did := range []string{did1, qualified} {
+		unqualified, err := UnqualifyDID(did)
+		if err != nil {
+			t.Fatalf("Error received from UnqualifyDID: %s", err)
+		}
+		if unqualified != did1 {
+			t.Fatalf("Expecting [%s] but got [%s]", did1, unqualified)
+		}
+	}
+
+	for _, did := range []string{"did:example:" + did1, SovPrefix + invalidDID, SovPrefix} {
+		if _, err := UnqualifyDID(did); err == nil {
+			t.Fatalf("Expecting error for DID [%s]", did)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	if err := ValidateSeed(nil); err != nil {
+		t.Fatalf("Error received from ValidateSeed for nil seed: %s", err)
+	}
+	if err := ValidateSeed(make([]byte, SeedSize)); err != nil {
+		t.Fatalf("Error received from ValidateSeed: %s", err)
+	}
+	if err := ValidateSeed(make([]byte, SeedSize-1)); err == nil {
+		t.Fatalf("Expecting error for short seed")
+	}
+
+	if err := ValidateCryptoType(""); err != nil {
+		t.Fatalf("Error received from ValidateCryptoType for default crypto type: %s", err)
+	}
+	if err := ValidateCryptoType(CryptoTypeEd25519); err != nil {
+		t.Fatalf("Error received from ValidateCryptoType: %s", err)
+	}
+	if err := ValidateCryptoType("secp256k1"); err == nil {
+		t.Fatalf("Expecting error for unsupported crypto type")
+	}
+
+	if encoded := EncodeSeed(nil); encoded != "" {
+		t.Fatalf("Expecting empty string for nil seed but got [%s]", encoded)
+	}
+	// libindy only decodes seeds that end with '='
+	encoded := EncodeSeed([]byte("00000000000000000000000000000My1"))
+	if encoded != "MDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDBNeTE=" {
+		t.Fatalf("Unexpected encoded seed [%s]", encoded)
+	}
+}
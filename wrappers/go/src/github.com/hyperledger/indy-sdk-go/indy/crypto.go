@@ -76,3 +76,60 @@ func AuthDecrypt(walletHandle types.Handle, recipientVK string, message []byte,
 	errCode := C.indy_crypto_auth_decrypt((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csRecipientVK, (*C.indy_u8_t)(cbMessage), (C.indy_u32_t)(len(message)), StringAndBytes())
 	return indyerror.New(int32(errCode))
 }
+
+func Sign(walletHandle types.Handle, signerVK string, message []byte, cb callback.Callback) error {
+	csSignerVK := newChar(signerVK)
+	defer freeChar(csSignerVK)
+
+	cbMessage := C.CBytes(message)
+	defer C.free(unsafe.Pointer(cbMessage))
+
+	handle := callback.Register(cb)
+	errCode := C.indy_crypto_sign((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csSignerVK, (*C.indy_u8_t)(cbMessage), (C.indy_u32_t)(len(message)), Bytes())
+	return indyerror.New(int32(errCode))
+}
+
+func Verify(signerVK string, message, signature []byte, cb callback.Callback) error {
+	csSignerVK := newChar(signerVK)
+	defer freeChar(csSignerVK)
+
+	cbMessage := C.CBytes(message)
+	defer C.free(unsafe.Pointer(cbMessage))
+
+	cbSignature := C.CBytes(signature)
+	defer C.free(unsafe.Pointer(cbSignature))
+
+	handle := callback.Register(cb)
+	errCode := C.indy_crypto_verify((C.indy_handle_t)(handle), csSignerVK, (*C.indy_u8_t)(cbMessage), (C.indy_u32_t)(len(message)), (*C.indy_u8_t)(cbSignature), (C.indy_u32_t)(len(signature)), Bool())
+	return indyerror.New(int32(errCode))
+}
+
+func CreateKey(walletHandle types.Handle, keyJSON string, cb callback.Callback) error {
+	csKeyJSON := newChar(keyJSON)
+	defer freeChar(csKeyJSON)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_create_key((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csKeyJSON, String())
+	return indyerror.New(int32(errCode))
+}
+
+func SetKeyMetadata(walletHandle types.Handle, verKey, metadata string, cb callback.Callback) error {
+	csVerKey := newChar(verKey)
+	defer freeChar(csVerKey)
+
+	csMetadata := newChar(metadata)
+	defer freeChar(csMetadata)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_set_key_metadata((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csVerKey, csMetadata, Default())
+	return indyerror.New(int32(errCode))
+}
+
+func GetKeyMetadata(walletHandle types.Handle, verKey string, cb callback.Callback) error {
+	csVerKey := newChar(verKey)
+	defer freeChar(csVerKey)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_get_key_metadata((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csVerKey, String())
+	return indyerror.New(int32(errCode))
+}
@@ -40,3 +40,98 @@ func KeyForDID(poolHandle types.Handle, walletHandle types.Handle, did string, c
 	errCode := C.indy_key_for_did((C.indy_handle_t)(handle), (C.indy_handle_t)(poolHandle), (C.indy_handle_t)(walletHandle), csDID, String())
 	return indyerror.New(int32(errCode))
 }
+
+func ReplaceKeysStart(walletHandle types.Handle, did, identityJSON string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+	csIdentityJSON := newChar(identityJSON)
+	defer freeChar(csIdentityJSON)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_replace_keys_start((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, csIdentityJSON, String())
+	return indyerror.New(int32(errCode))
+}
+
+func ReplaceKeysApply(walletHandle types.Handle, did string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_replace_keys_apply((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, Default())
+	return indyerror.New(int32(errCode))
+}
+
+func StoreTheirDID(walletHandle types.Handle, identityJSON string, cb callback.Callback) error {
+	csIdentityJSON := newChar(identityJSON)
+	defer freeChar(csIdentityJSON)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_store_their_did((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csIdentityJSON, Default())
+	return indyerror.New(int32(errCode))
+}
+
+func KeyForLocalDID(walletHandle types.Handle, did string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_key_for_local_did((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, String())
+	return indyerror.New(int32(errCode))
+}
+
+func SetDIDMetadata(walletHandle types.Handle, did, metadata string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+	csMetadata := newChar(metadata)
+	defer freeChar(csMetadata)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_set_did_metadata((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, csMetadata, Default())
+	return indyerror.New(int32(errCode))
+}
+
+func GetDIDMetadata(walletHandle types.Handle, did string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_get_did_metadata((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, String())
+	return indyerror.New(int32(errCode))
+}
+
+func GetMyDIDWithMeta(walletHandle types.Handle, did string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_get_my_did_with_meta((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, String())
+	return indyerror.New(int32(errCode))
+}
+
+func ListMyDIDsWithMeta(walletHandle types.Handle, cb callback.Callback) error {
+	handle := callback.Register(cb)
+	errCode := C.indy_list_my_dids_with_meta((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), String())
+	return indyerror.New(int32(errCode))
+}
+
+func SetEndpointForDID(walletHandle types.Handle, did, address, transportKey string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+	csAddress := newChar(address)
+	defer freeChar(csAddress)
+	csTransportKey := newChar(transportKey)
+	defer freeChar(csTransportKey)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_set_endpoint_for_did((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csDID, csAddress, csTransportKey, Default())
+	return indyerror.New(int32(errCode))
+}
+
+func GetEndpointForDID(walletHandle types.Handle, poolHandle types.Handle, did string, cb callback.Callback) error {
+	csDID := newChar(did)
+	defer freeChar(csDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_get_endpoint_for_did((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), (C.indy_handle_t)(poolHandle), csDID, String2())
+	return indyerror.New(int32(errCode))
+}
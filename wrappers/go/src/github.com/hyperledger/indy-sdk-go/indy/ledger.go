@@ -51,6 +51,44 @@ func BuildNYMRequest(submitterDID, targetDID, verkey string, alias *types.Alias,
 	return indyerror.New(int32(errCode))
 }
 
+func BuildGetNYMRequest(submitterDID, targetDID string, cb callback.Callback) error {
+	csSubmitterDID := newChar(submitterDID)
+	defer freeChar(csSubmitterDID)
+
+	csTargetDID := newChar(targetDID)
+	defer freeChar(csTargetDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_build_get_nym_request((C.indy_handle_t)(handle), csSubmitterDID, csTargetDID, String())
+	return indyerror.New(int32(errCode))
+}
+
+func BuildGetAttribRequest(submitterDID, targetDID, raw, hash, enc string, cb callback.Callback) error {
+	csSubmitterDID := newChar(submitterDID)
+	defer freeChar(csSubmitterDID)
+
+	csTargetDID := newChar(targetDID)
+	defer freeChar(csTargetDID)
+
+	var csRaw, csHash, csEnc *C.char
+	if raw != "" {
+		csRaw = newChar(raw)
+		defer freeChar(csRaw)
+	}
+	if hash != "" {
+		csHash = newChar(hash)
+		defer freeChar(csHash)
+	}
+	if enc != "" {
+		csEnc = newChar(enc)
+		defer freeChar(csEnc)
+	}
+
+	handle := callback.Register(cb)
+	errCode := C.indy_build_get_attrib_request((C.indy_handle_t)(handle), csSubmitterDID, csTargetDID, csHash, csRaw, csEnc, String())
+	return indyerror.New(int32(errCode))
+}
+
 func SignAndSubmitRequest(poolHandle types.Handle, walletHandle types.Handle, submitterDID, requestJSON string, cb callback.Callback) error {
 	csSubmitterDID := newChar(submitterDID)
 	defer freeChar(csSubmitterDID)
@@ -137,3 +175,36 @@ func ParseGetCredDefResponse(response string, cb callback.Callback) error {
 	errCode := C.indy_parse_get_cred_def_response((C.indy_handle_t)(handle), csResponse, String2())
 	return indyerror.New(int32(errCode))
 }
+
+func BuildPoolUpgradeRequest(submitterDID, name, version, action, sha256 string, timeout int32, schedule, justification string, reinstall, force bool, cb callback.Callback) error {
+	csSubmitterDID := newChar(submitterDID)
+	defer freeChar(csSubmitterDID)
+
+	csName := newChar(name)
+	defer freeChar(csName)
+
+	csVersion := newChar(version)
+	defer freeChar(csVersion)
+
+	csAction := newChar(action)
+	defer freeChar(csAction)
+
+	csSHA256 := newChar(sha256)
+	defer freeChar(csSHA256)
+
+	var csSchedule *C.char
+	if schedule != "" {
+		csSchedule = newChar(schedule)
+		defer freeChar(csSchedule)
+	}
+
+	var csJustification *C.char
+	if justification != "" {
+		csJustification = newChar(justification)
+		defer freeChar(csJustification)
+	}
+
+	handle := callback.Register(cb)
+	errCode := C.indy_build_pool_upgrade_request((C.indy_handle_t)(handle), csSubmitterDID, csName, csVersion, csAction, csSHA256, (C.indy_i32_t)(timeout), csSchedule, csJustification, cBool(reinstall), cBool(force), String())
+	return indyerror.New(int32(errCode))
+}
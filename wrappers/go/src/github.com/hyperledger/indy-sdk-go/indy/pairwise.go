@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package indy
+
+import (
+	"github.com/hyperledger/indy-sdk-go/common/callback"
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/common/types"
+)
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../../../../../../../../libindy/include
+#cgo CFLAGS: -I/home/indy/libindy/include
+#cgo LDFLAGS: -lindy
+
+#include <indy_mod.h>
+#include <indy_types.h>
+#include <indy_pairwise.h>
+*/
+import "C"
+
+func IsPairwiseExists(walletHandle types.Handle, theirDID string, cb callback.Callback) error {
+	csTheirDID := newChar(theirDID)
+	defer freeChar(csTheirDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_is_pairwise_exists((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csTheirDID, Bool())
+	return indyerror.New(int32(errCode))
+}
+
+func CreatePairwise(walletHandle types.Handle, theirDID, myDID, metadata string, cb callback.Callback) error {
+	csTheirDID := newChar(theirDID)
+	defer freeChar(csTheirDID)
+
+	csMyDID := newChar(myDID)
+	defer freeChar(csMyDID)
+
+	var csMetadata *C.char
+	if metadata != "" {
+		csMetadata = newChar(metadata)
+		defer freeChar(csMetadata)
+	}
+
+	handle := callback.Register(cb)
+	errCode := C.indy_create_pairwise((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csTheirDID, csMyDID, csMetadata, Default())
+	return indyerror.New(int32(errCode))
+}
+
+func ListPairwise(walletHandle types.Handle, cb callback.Callback) error {
+	handle := callback.Register(cb)
+	errCode := C.indy_list_pairwise((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), String())
+	return indyerror.New(int32(errCode))
+}
+
+func GetPairwise(walletHandle types.Handle, theirDID string, cb callback.Callback) error {
+	csTheirDID := newChar(theirDID)
+	defer freeChar(csTheirDID)
+
+	handle := callback.Register(cb)
+	errCode := C.indy_get_pairwise((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csTheirDID, String())
+	return indyerror.New(int32(errCode))
+}
+
+func SetPairwiseMetadata(walletHandle types.Handle, theirDID, metadata string, cb callback.Callback) error {
+	csTheirDID := newChar(theirDID)
+	defer freeChar(csTheirDID)
+
+	var csMetadata *C.char
+	if metadata != "" {
+		csMetadata = newChar(metadata)
+		defer freeChar(csMetadata)
+	}
+
+	handle := callback.Register(cb)
+	errCode := C.indy_set_pairwise_metadata((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), csTheirDID, csMetadata, Default())
+	return indyerror.New(int32(errCode))
+}
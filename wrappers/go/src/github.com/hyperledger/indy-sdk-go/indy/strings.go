@@ -21,3 +21,11 @@ func newChar(s string) *C.char {
 func freeChar(cs *C.char) {
 	C.free(unsafe.Pointer(cs))
 }
+
+// cBool converts a Go bool into an Indy bool
+func cBool(b bool) C.uint {
+	if b {
+		return 1
+	}
+	return 0
+}
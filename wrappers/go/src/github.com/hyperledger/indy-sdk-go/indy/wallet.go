@@ -94,3 +94,9 @@ func CloseWallet(walletHandle types.Handle, cb callback.Callback) error {
 	errCode := C.indy_close_wallet((C.indy_handle_t)(handle), (C.indy_handle_t)(walletHandle), Default())
 	return indyerror.New(int32(errCode))
 }
+
+func ListWallets(cb callback.Callback) error {
+	handle := callback.Register(cb)
+	errCode := C.indy_list_wallets((C.indy_handle_t)(handle), String())
+	return indyerror.New(int32(errCode))
+}
@@ -0,0 +1,220 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package indy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/indy-sdk-go/common/callback"
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+)
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../../../../../../../../libindy/include
+#cgo CFLAGS: -I/home/indy/libindy/include
+#cgo LDFLAGS: -lindy
+
+#include <stdlib.h>
+#include <stdint.h>
+#include <indy_mod.h>
+#include <indy_types.h>
+#include <indy_wallet.h>
+
+extern int32_t wallet_type_create(int32_t slot, char *name, char *config, char *credentials);
+extern int32_t wallet_type_open(int32_t slot, char *name, char *config, char *runtime_config, char *credentials, int32_t *handle);
+extern int32_t wallet_type_delete(int32_t slot, char *name, char *config, char *credentials);
+extern int32_t wallet_type_set(int32_t handle, char *key, char *value);
+extern int32_t wallet_type_get(int32_t handle, char *key, char **value);
+extern int32_t wallet_type_get_not_expired(int32_t handle, char *key, char **value);
+extern int32_t wallet_type_list(int32_t handle, char *key_prefix, char **values_json);
+extern int32_t wallet_type_close(int32_t handle);
+extern int32_t wallet_type_free(int32_t handle, char *str);
+
+// libindy doesn't pass the wallet type to the create, open and delete handlers
+// so each registered type gets its own set of handlers (a slot) which forward
+// the slot number to Go.
+#define WALLET_TYPE_SLOT(i) \
+static indy_error_t wallet_type_create_##i(const char *name, const char *config, const char *credentials) { \
+	return (indy_error_t)wallet_type_create(i, (char *)name, (char *)config, (char *)credentials); \
+} \
+static indy_error_t wallet_type_open_##i(const char *name, const char *config, const char *runtime_config, const char *credentials, indy_handle_t *handle) { \
+	return (indy_error_t)wallet_type_open(i, (char *)name, (char *)config, (char *)runtime_config, (char *)credentials, handle); \
+} \
+static indy_error_t wallet_type_delete_##i(const char *name, const char *config, const char *credentials) { \
+	return (indy_error_t)wallet_type_delete(i, (char *)name, (char *)config, (char *)credentials); \
+}
+
+WALLET_TYPE_SLOT(0)
+WALLET_TYPE_SLOT(1)
+WALLET_TYPE_SLOT(2)
+WALLET_TYPE_SLOT(3)
+WALLET_TYPE_SLOT(4)
+WALLET_TYPE_SLOT(5)
+WALLET_TYPE_SLOT(6)
+WALLET_TYPE_SLOT(7)
+
+// The remaining handlers are routed by the wallet handle which is unique across all types.
+static indy_error_t wallet_type_set_handler(indy_handle_t handle, const char *key, const char *value) {
+	return (indy_error_t)wallet_type_set(handle, (char *)key, (char *)value);
+}
+
+static indy_error_t wallet_type_get_handler(indy_handle_t handle, const char *key, const char **const value_ptr) {
+	return (indy_error_t)wallet_type_get(handle, (char *)key, (char **)value_ptr);
+}
+
+static indy_error_t wallet_type_get_not_expired_handler(indy_handle_t handle, const char *key, const char **const value_ptr) {
+	return (indy_error_t)wallet_type_get_not_expired(handle, (char *)key, (char **)value_ptr);
+}
+
+static indy_error_t wallet_type_list_handler(indy_handle_t handle, const char *key_prefix, const char **const values_json_ptr) {
+	return (indy_error_t)wallet_type_list(handle, (char *)key_prefix, (char **)values_json_ptr);
+}
+
+static indy_error_t wallet_type_close_handler(indy_handle_t handle) {
+	return (indy_error_t)wallet_type_close(handle);
+}
+
+static indy_error_t wallet_type_free_handler(indy_handle_t handle, const char *str) {
+	return (indy_error_t)wallet_type_free(handle, (char *)str);
+}
+
+#define REGISTER_WALLET_TYPE_SLOT(i) \
+	case i: \
+		return indy_register_wallet_type(command_handle, xtype, \
+			wallet_type_create_##i, wallet_type_open_##i, \
+			wallet_type_set_handler, wallet_type_get_handler, wallet_type_get_not_expired_handler, \
+			wallet_type_list_handler, wallet_type_close_handler, wallet_type_delete_##i, \
+			wallet_type_free_handler, cb);
+
+static indy_error_t register_wallet_type(indy_handle_t command_handle, const char *xtype, int slot, void (*cb)(indy_handle_t, indy_error_t)) {
+	switch (slot) {
+	REGISTER_WALLET_TYPE_SLOT(0)
+	REGISTER_WALLET_TYPE_SLOT(1)
+	REGISTER_WALLET_TYPE_SLOT(2)
+	REGISTER_WALLET_TYPE_SLOT(3)
+	REGISTER_WALLET_TYPE_SLOT(4)
+	REGISTER_WALLET_TYPE_SLOT(5)
+	REGISTER_WALLET_TYPE_SLOT(6)
+	REGISTER_WALLET_TYPE_SLOT(7)
+	}
+	return CommonInvalidState;
+}
+*/
+import "C"
+
+// MaxWalletTypes is the maximum number of wallet types that may be registered
+const MaxWalletTypes = 8
+
+// WalletType is a wallet type implemented in Go. The functions are invoked by
+// libindy from its own threads so implementations must be safe for concurrent use.
+type WalletType interface {
+	Create(name, config, credentials string) error
+	Open(name, config, runtimeConfig, credentials string) (WalletStorage, error)
+	Delete(name, config, credentials string) error
+}
+
+// WalletStorage is an open wallet of a WalletType
+type WalletStorage interface {
+	Set(key, value string) error
+	Get(key string) (string, error)
+	GetNotExpired(key string) (string, error)
+	List(keyPrefix string) (valuesJSON string, err error)
+	Close() error
+}
+
+type walletTypeRegistry struct {
+	mutex      sync.RWMutex
+	slots      [MaxWalletTypes]WalletType
+	storage    map[int32]WalletStorage
+	nextHandle int32
+}
+
+var walletTypes = &walletTypeRegistry{
+	storage: make(map[int32]WalletStorage),
+}
+
+func RegisterWalletType(xtype string, walletType WalletType, cb callback.Callback) error {
+	slot, err := walletTypes.reserve(walletType)
+	if err != nil {
+		return err
+	}
+
+	csXType := newChar(xtype)
+	defer freeChar(csXType)
+
+	handle := callback.Register(func(err error, data callback.Data) {
+		if err != nil {
+			walletTypes.release(slot)
+		}
+		cb(err, data)
+	})
+	errCode := C.register_wallet_type((C.indy_handle_t)(handle), csXType, C.int(slot), Default())
+	if int32(errCode) != indyerror.Success {
+		callback.Remove(handle)
+		walletTypes.release(slot)
+	}
+	return indyerror.New(int32(errCode))
+}
+
+func (r *walletTypeRegistry) reserve(walletType WalletType) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, t := range r.slots {
+		if t == nil {
+			r.slots[i] = walletType
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no more than %d wallet types may be registered", MaxWalletTypes)
+}
+
+func (r *walletTypeRegistry) release(slot int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.slots[slot] = nil
+}
+
+func (r *walletTypeRegistry) walletType(slot int32) (WalletType, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if slot < 0 || slot >= MaxWalletTypes || r.slots[slot] == nil {
+		return nil, false
+	}
+	return r.slots[slot], true
+}
+
+func (r *walletTypeRegistry) add(storage WalletStorage) int32 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.nextHandle++
+	r.storage[r.nextHandle] = storage
+	return r.nextHandle
+}
+
+func (r *walletTypeRegistry) get(handle int32) (WalletStorage, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	storage, ok := r.storage[handle]
+	return storage, ok
+}
+
+func (r *walletTypeRegistry) remove(handle int32) (WalletStorage, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	storage, ok := r.storage[handle]
+	if ok {
+		delete(r.storage, handle)
+	}
+	return storage, ok
+}
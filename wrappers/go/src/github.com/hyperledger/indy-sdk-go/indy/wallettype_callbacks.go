@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package indy
+
+import (
+	"unsafe"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+)
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+
+extern int32_t wallet_type_create(int32_t slot, char *name, char *config, char *credentials);
+extern int32_t wallet_type_open(int32_t slot, char *name, char *config, char *runtime_config, char *credentials, int32_t *handle);
+extern int32_t wallet_type_delete(int32_t slot, char *name, char *config, char *credentials);
+extern int32_t wallet_type_set(int32_t handle, char *key, char *value);
+extern int32_t wallet_type_get(int32_t handle, char *key, char **value);
+extern int32_t wallet_type_get_not_expired(int32_t handle, char *key, char **value);
+extern int32_t wallet_type_list(int32_t handle, char *key_prefix, char **values_json);
+extern int32_t wallet_type_close(int32_t handle);
+extern int32_t wallet_type_free(int32_t handle, char *str);
+*/
+import "C"
+
+//export wallet_type_create
+func wallet_type_create(slot int32, name, config, credentials *C.char) int32 {
+	walletType, ok := walletTypes.walletType(slot)
+	if !ok {
+		logger.Errorf("Wallet type not registered for slot %d", slot)
+		return indyerror.CommonInvalidState
+	}
+	return errorCode(walletType.Create(goString(name), goString(config), goString(credentials)))
+}
+
+//export wallet_type_open
+func wallet_type_open(slot int32, name, config, runtimeConfig, credentials *C.char, handle *C.int32_t) int32 {
+	walletType, ok := walletTypes.walletType(slot)
+	if !ok {
+		logger.Errorf("Wallet type not registered for slot %d", slot)
+		return indyerror.CommonInvalidState
+	}
+
+	storage, err := walletType.Open(goString(name), goString(config), goString(runtimeConfig), goString(credentials))
+	if err != nil {
+		return errorCode(err)
+	}
+
+	*handle = C.int32_t(walletTypes.add(storage))
+	return indyerror.Success
+}
+
+//export wallet_type_delete
+func wallet_type_delete(slot int32, name, config, credentials *C.char) int32 {
+	walletType, ok := walletTypes.walletType(slot)
+	if !ok {
+		logger.Errorf("Wallet type not registered for slot %d", slot)
+		return indyerror.CommonInvalidState
+	}
+	return errorCode(walletType.Delete(goString(name), goString(config), goString(credentials)))
+}
+
+//export wallet_type_set
+func wallet_type_set(handle int32, key, value *C.char) int32 {
+	storage, ok := walletTypes.get(handle)
+	if !ok {
+		return indyerror.WalletInvalidHandle
+	}
+	return errorCode(storage.Set(C.GoString(key), C.GoString(value)))
+}
+
+//export wallet_type_get
+func wallet_type_get(handle int32, key *C.char, value **C.char) int32 {
+	storage, ok := walletTypes.get(handle)
+	if !ok {
+		return indyerror.WalletInvalidHandle
+	}
+
+	v, err := storage.Get(C.GoString(key))
+	if err != nil {
+		return errorCode(err)
+	}
+
+	*value = C.CString(v)
+	return indyerror.Success
+}
+
+//export wallet_type_get_not_expired
+func wallet_type_get_not_expired(handle int32, key *C.char, value **C.char) int32 {
+	storage, ok := walletTypes.get(handle)
+	if !ok {
+		return indyerror.WalletInvalidHandle
+	}
+
+	v, err := storage.GetNotExpired(C.GoString(key))
+	if err != nil {
+		return errorCode(err)
+	}
+
+	*value = C.CString(v)
+	return indyerror.Success
+}
+
+//export wallet_type_list
+func wallet_type_list(handle int32, keyPrefix *C.char, valuesJSON **C.char) int32 {
+	storage, ok := walletTypes.get(handle)
+	if !ok {
+		return indyerror.WalletInvalidHandle
+	}
+
+	values, err := storage.List(C.GoString(keyPrefix))
+	if err != nil {
+		return errorCode(err)
+	}
+
+	*valuesJSON = C.CString(values)
+	return indyerror.Success
+}
+
+//export wallet_type_close
+func wallet_type_close(handle int32) int32 {
+	storage, ok := walletTypes.remove(handle)
+	if !ok {
+		return indyerror.WalletInvalidHandle
+	}
+	return errorCode(storage.Close())
+}
+
+//export wallet_type_free
+func wallet_type_free(handle int32, s *C.char) int32 {
+	C.free(unsafe.Pointer(s))
+	return indyerror.Success
+}
+
+// goString converts an optional C string into a Go string
+func goString(cs *C.char) string {
+	if cs == nil {
+		return ""
+	}
+	return C.GoString(cs)
+}
+
+// errorCode converts an error returned by a wallet type into an Indy error code.
+// Errors that don't carry an Indy error code are reported as CommonInvalidState.
+func errorCode(err error) int32 {
+	if err == nil {
+		return indyerror.Success
+	}
+
+	code := indyerror.Code(err)
+	if code == indyerror.Undefined {
+		logger.Warnf("Wallet type returned error: %s", err)
+		return indyerror.CommonInvalidState
+	}
+	return code
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/pool"
+)
+
+// ErrAttribNotFound is returned by ParseGetAttribResponse if the attribute isn't written to the ledger
+var ErrAttribNotFound = fmt.Errorf("attribute not found on the ledger")
+
+// ParseGetAttribResponse parses the response of a GET_ATTRIB request for a raw attribute and
+// returns the attribute json, e.g. {"endpoint":{"ha":"127.0.0.1:9700"}}.
+// ErrAttribNotFound is returned if the attribute isn't written to the ledger.
+//
+// response The GET_ATTRIB response json.
+func ParseGetAttribResponse(response string) (string, error) {
+	var reply struct {
+		Op     string `json:"op"`
+		Reason string `json:"reason"`
+		Result struct {
+			Data *string `json:"data"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(response), &reply); err != nil {
+		return "", fmt.Errorf("invalid GET_ATTRIB response: %s", err)
+	}
+	if reply.Op != "REPLY" {
+		return "", &RejectedError{TxnType: "GET_ATTRIB", Op: reply.Op, Reason: reply.Reason}
+	}
+	if reply.Result.Data == nil || *reply.Result.Data == "" {
+		return "", ErrAttribNotFound
+	}
+	return *reply.Result.Data, nil
+}
+
+// GetRawAttrib reads the raw attribute with the given name of a DID from the ledger and
+// returns the attribute json. ErrAttribNotFound is returned if the attribute isn't written
+// to the ledger.
+//
+// pool         The pool.
+// submitterDID DID of read request sender.
+// did          The DID whose attribute is read.
+// name         The name of the attribute, e.g. endpoint.
+func GetRawAttrib(pool *pool.Pool, submitterDID, did, name string) (string, error) {
+	request, err := BuildGetAttribRequest(submitterDID, did, name, "", "")
+	if err != nil {
+		return "", err
+	}
+	response, err := SubmitRequest(pool, request)
+	if err != nil {
+		return "", err
+	}
+	return ParseGetAttribResponse(response)
+}
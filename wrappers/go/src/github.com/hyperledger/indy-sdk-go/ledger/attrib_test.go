@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+)
+
+func TestParseGetAttribResponse(t *testing.T) {
+	response := `{"op":"REPLY","result":{"type":"104","dest":"VsKV7grR1BUE29mG2Fm2kX","raw":"endpoint","seqNo":13,"data":"{\"endpoint\":{\"ha\":\"127.0.0.1:9700\"}}"}}`
+
+	attrib, err := ParseGetAttribResponse(response)
+	if err != nil {
+		t.Fatalf("Error received from ParseGetAttribResponse: %s", err)
+	}
+	if attrib != `{"endpoint":{"ha":"127.0.0.1:9700"}}` {
+		t.Fatalf("Unexpected attribute [%s]", attrib)
+	}
+
+	_, err = ParseGetAttribResponse(`{"op":"REPLY","result":{"type":"104","raw":"endpoint","data":null}}`)
+	if err != ErrAttribNotFound {
+		t.Fatalf("Expecting error [%s] but got [%v]", ErrAttribNotFound, err)
+	}
+
+	_, err = ParseGetAttribResponse(`{"op":"REQNACK","reason":"client request invalid"}`)
+	if _, ok := err.(*RejectedError); !ok {
+		t.Fatalf("Expecting RejectedError but got [%v]", err)
+	}
+}
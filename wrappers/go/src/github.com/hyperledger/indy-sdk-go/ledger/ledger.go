@@ -37,6 +37,35 @@ func BuildNYMRequest(submitterDID, targetDID, verkey string, alias *types.Alias,
 	return
 }
 
+// BuildGetNYMRequest builds a GET_NYM request. Request to get information about a DID (NYM).
+//
+// submitterDid DID of read request sender.
+// targetDid    Target DID as base58-encoded string for 16 or 32 bit DID value.
+func BuildGetNYMRequest(submitterDID, targetDID string) (request string, err error) {
+	requestChan, errChan := buildGetNYMRequest(submitterDID, targetDID)
+	select {
+	case request = <-requestChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// BuildGetAttribRequest builds a GET_ATTRIB request. Request to get information about an Attribute for the specified DID.
+//
+// submitterDid DID of read request sender.
+// targetDid    Target DID as base58-encoded string for 16 or 32 bit DID value.
+// raw          Requested attribute name. Empty if hash or enc is specified.
+// hash         Requested attribute hash. Empty if raw or enc is specified.
+// enc          Requested attribute encrypted value. Empty if raw or hash is specified.
+func BuildGetAttribRequest(submitterDID, targetDID, raw, hash, enc string) (request string, err error) {
+	requestChan, errChan := buildGetAttribRequest(submitterDID, targetDID, raw, hash, enc)
+	select {
+	case request = <-requestChan:
+	case err = <-errChan:
+	}
+	return
+}
+
 // SignAndSubmitRequest signs and submits request message to validator pool.
 //
 // Adds submitter information to passed request json, signs it with submitter
@@ -190,6 +219,30 @@ func ParseGetCredDefResponse(response string) (id, json string, err error) {
 	return
 }
 
+// BuildPoolUpgradeRequest builds a POOL_UPGRADE request. Request to upgrade the Pool (sent by Trustee).
+// It upgrades the specified Nodes (either all nodes in the Pool, or some specific ones).
+//
+// submitterDid  DID of the submitter stored in secured Wallet.
+// name          Human-readable name for the upgrade.
+// version       The version of indy-node package we perform upgrade to.
+//               Must be greater than existing one (or equal if reinstall flag is True).
+// action        Either start or cancel.
+// sha256        sha256 hash of the package.
+// timeout       (Optional) Limits upgrade time on each Node, in minutes. Zero means no limit is sent.
+// schedule      (Optional) Schedule of when to perform upgrade on each node. Map Node DIDs to upgrade time.
+// justification (Optional) justification string for this particular Upgrade.
+// reinstall     Whether it's allowed to re-install the same version.
+// force         Whether we should apply transaction (schedule Upgrade) without waiting
+//               for consensus of this transaction.
+func BuildPoolUpgradeRequest(submitterDID, name, version, action, sha256 string, timeout int, schedule, justification string, reinstall, force bool) (request string, err error) {
+	requestChan, errChan := buildPoolUpgradeRequest(submitterDID, name, version, action, sha256, timeout, schedule, justification, reinstall, force)
+	select {
+	case request = <-requestChan:
+	case err = <-errChan:
+	}
+	return
+}
+
 func buildNYMRequest(submitterDID, targetDID, verkey string, alias *types.Alias, role *role.Role) (chan string, chan error) {
 	logger.Debugf("Building NYM request - SubmitterDID [%s], TargetDID [%s], VerKey [%s], Alias [%s], Role [%v]", submitterDID, targetDID, verkey, alias, role)
 
@@ -222,6 +275,74 @@ func buildNYMRequest(submitterDID, targetDID, verkey string, alias *types.Alias,
 	return reqChan, errChan
 }
 
+func buildGetNYMRequest(submitterDID, targetDID string) (chan string, chan error) {
+	logger.Debugf("Building get-NYM request - SubmitterDID [%s], TargetDID [%s]", submitterDID, targetDID)
+
+	reqChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if submitterDID == "" {
+		errChan <- fmt.Errorf("submitter DID must be specified")
+		return reqChan, errChan
+	}
+	if targetDID == "" {
+		errChan <- fmt.Errorf("target DID must be specified")
+		return reqChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			reqChan <- data.(string)
+		}
+	}
+
+	err := indy.BuildGetNYMRequest(submitterDID, targetDID, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return reqChan, errChan
+}
+
+func buildGetAttribRequest(submitterDID, targetDID, raw, hash, enc string) (chan string, chan error) {
+	logger.Debugf("Building get-attrib request - SubmitterDID [%s], TargetDID [%s], Raw [%s], Hash [%s], Enc [%s]", submitterDID, targetDID, raw, hash, enc)
+
+	reqChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if submitterDID == "" {
+		errChan <- fmt.Errorf("submitter DID must be specified")
+		return reqChan, errChan
+	}
+	if targetDID == "" {
+		errChan <- fmt.Errorf("target DID must be specified")
+		return reqChan, errChan
+	}
+	if raw == "" && hash == "" && enc == "" {
+		errChan <- fmt.Errorf("one of raw, hash or enc must be specified")
+		return reqChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			reqChan <- data.(string)
+		}
+	}
+
+	err := indy.BuildGetAttribRequest(submitterDID, targetDID, raw, hash, enc, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return reqChan, errChan
+}
+
 func signAndSubmitRequest(pool *pool.Pool, wallet *wallet.Wallet, submitterDID, requestJSON string) (chan string, chan error) {
 	logger.Debugf("Signing and submitting request - Pool [%s], Wallet [%s], SubmitterDID [%s], JSON [%s]", pool.Name, wallet.Name, submitterDID, requestJSON)
 
@@ -245,7 +366,18 @@ func signAndSubmitRequest(pool *pool.Pool, wallet *wallet.Wallet, submitterDID,
 		}
 	}
 
-	err := indy.SignAndSubmitRequest(pool.Handle(), wallet.Handle(), submitterDID, requestJSON, cb)
+	poolHandle, err := pool.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.SignAndSubmitRequest(poolHandle, walletHandle, submitterDID, requestJSON, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -273,7 +405,13 @@ func submitRequest(pool *pool.Pool, requestJSON string) (chan string, chan error
 		}
 	}
 
-	err := indy.SubmitRequest(pool.Handle(), requestJSON, cb)
+	poolHandle, err := pool.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return respChan, errChan
+	}
+
+	err = indy.SubmitRequest(poolHandle, requestJSON, cb)
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -478,3 +616,53 @@ func parseGetCredDefResponse(response string) (chan *parseResponse, chan error)
 
 	return resultChan, errChan
 }
+
+func buildPoolUpgradeRequest(submitterDID, name, version, action, sha256 string, timeout int, schedule, justification string, reinstall, force bool) (chan string, chan error) {
+	logger.Debugf("Building pool upgrade request - SubmitterDID [%s], Name [%s], Version [%s], Action [%s], SHA256 [%s], Timeout [%d], Schedule [%s], Justification [%s], Reinstall [%t], Force [%t]", submitterDID, name, version, action, sha256, timeout, schedule, justification, reinstall, force)
+
+	reqChan := make(chan string)
+	errChan := make(chan error, 1)
+
+	if submitterDID == "" {
+		errChan <- fmt.Errorf("submitter DID must be specified")
+		return reqChan, errChan
+	}
+	if name == "" {
+		errChan <- fmt.Errorf("name must be specified")
+		return reqChan, errChan
+	}
+	if version == "" {
+		errChan <- fmt.Errorf("version must be specified")
+		return reqChan, errChan
+	}
+	if action == "" {
+		errChan <- fmt.Errorf("action must be specified")
+		return reqChan, errChan
+	}
+	if sha256 == "" {
+		errChan <- fmt.Errorf("sha256 must be specified")
+		return reqChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			reqChan <- data.(string)
+		}
+	}
+
+	// libindy expects a negative timeout when none is given
+	indyTimeout := int32(-1)
+	if timeout > 0 {
+		indyTimeout = int32(timeout)
+	}
+
+	err := indy.BuildPoolUpgradeRequest(submitterDID, name, version, action, sha256, indyTimeout, schedule, justification, reinstall, force, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return reqChan, errChan
+}
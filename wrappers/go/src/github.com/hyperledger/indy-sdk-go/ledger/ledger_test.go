@@ -50,6 +50,38 @@ func TestLedger(t *testing.T) {
 	t.Logf("Response received: %s", resp)
 }
 
+func TestClosedPoolAndWallet(t *testing.T) {
+	p, err := getPool(t, poolName, poolConfig)
+	if err != nil {
+		t.Fatalf("Error received from getPool: %s", err)
+	}
+	defer p.Close()
+
+	w, err := getWallet("closed_wallet", poolName)
+	if err != nil {
+		t.Fatalf("Error received from getWallet: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+
+	nymReq, err := BuildNYMRequest(did1, did2, verKey1, nil, nil)
+	if err != nil {
+		t.Fatalf("Error received from BuildNYMRequest: %s", err)
+	}
+
+	if _, err := SignAndSubmitRequest(p, w, did1, nymReq); err != wallet.ErrClosed {
+		t.Fatalf("Expecting error [%s] from SignAndSubmitRequest but got [%v]", wallet.ErrClosed, err)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+	if _, err := SubmitRequest(p, nymReq); err != pool.ErrClosed {
+		t.Fatalf("Expecting error [%s] from SubmitRequest but got [%v]", pool.ErrClosed, err)
+	}
+}
+
 func getPool(t *testing.T, poolName, configPath string) (*pool.Pool, error) {
 	err := pool.Create(poolName, configPath)
 	if err != nil && indyerror.Code(err) != indyerror.PoolLedgerConfigAlreadyExistsError {
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/pool"
+)
+
+// ErrNYMNotFound is returned by ParseGetNYMResponse if the DID isn't written to the ledger
+var ErrNYMNotFound = fmt.Errorf("NYM not found on the ledger")
+
+// NYM is the ledger record of a DID
+type NYM struct {
+	// DID is the DID of the NYM
+	DID string `json:"dest"`
+
+	// Identifier is the DID that wrote the NYM
+	Identifier string `json:"identifier"`
+
+	// Role is the role code of the DID. Empty for a common user.
+	Role string `json:"role"`
+
+	// VerKey is the verification key of the DID, which may be abbreviated ('~' prefix)
+	VerKey string `json:"verkey"`
+
+	// SeqNo is the sequence number of the transaction that last updated the NYM
+	SeqNo int64 `json:"seqNo"`
+
+	// TxnTime is the time (Unix seconds) of the transaction that last updated the NYM
+	TxnTime int64 `json:"txnTime"`
+}
+
+// ParseGetNYMResponse parses the response of a GET_NYM request.
+// ErrNYMNotFound is returned if the DID isn't written to the ledger.
+//
+// response The GET_NYM response json.
+func ParseGetNYMResponse(response string) (*NYM, error) {
+	var reply struct {
+		Op     string `json:"op"`
+		Reason string `json:"reason"`
+		Result struct {
+			Data    *string `json:"data"`
+			SeqNo   int64   `json:"seqNo"`
+			TxnTime int64   `json:"txnTime"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(response), &reply); err != nil {
+		return nil, fmt.Errorf("invalid GET_NYM response: %s", err)
+	}
+	if reply.Op != "REPLY" {
+		return nil, &RejectedError{TxnType: "GET_NYM", Op: reply.Op, Reason: reply.Reason}
+	}
+	if reply.Result.Data == nil || *reply.Result.Data == "" {
+		return nil, ErrNYMNotFound
+	}
+
+	nym := &NYM{}
+	if err := json.Unmarshal([]byte(*reply.Result.Data), nym); err != nil {
+		return nil, fmt.Errorf("invalid GET_NYM response data: %s", err)
+	}
+	if nym.SeqNo == 0 {
+		nym.SeqNo = reply.Result.SeqNo
+	}
+	if nym.TxnTime == 0 {
+		nym.TxnTime = reply.Result.TxnTime
+	}
+	return nym, nil
+}
+
+// GetNYM reads the NYM of the given DID from the ledger.
+// ErrNYMNotFound is returned if the DID isn't written to the ledger.
+//
+// pool         The pool.
+// submitterDID DID of read request sender.
+// did          The DID to read.
+func GetNYM(pool *pool.Pool, submitterDID, did string) (*NYM, error) {
+	request, err := BuildGetNYMRequest(submitterDID, did)
+	if err != nil {
+		return nil, err
+	}
+	response, err := SubmitRequest(pool, request)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetNYMResponse(response)
+}
+
+// RejectedError is returned if the ledger didn't accept a request
+type RejectedError struct {
+	// TxnType is the type of the transaction, e.g. NYM
+	TxnType string
+
+	// Op is the operation of the reply, e.g. REJECT or REQNACK
+	Op string
+
+	// Reason is the reason given by the ledger
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s request was not accepted [%s]: %s", e.TxnType, e.Op, e.Reason)
+}
+
+// CheckReply returns a *RejectedError if the ledger didn't accept the request.
+//
+// txnType  The type of the transaction, e.g. NYM, used in error messages.
+// response The response json.
+func CheckReply(txnType, response string) error {
+	var reply struct {
+		Op     string `json:"op"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(response), &reply); err != nil {
+		return fmt.Errorf("invalid %s response: %s", txnType, err)
+	}
+	if reply.Op != "REPLY" {
+		return &RejectedError{TxnType: txnType, Op: reply.Op, Reason: reply.Reason}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+)
+
+func TestParseGetNYMResponse(t *testing.T) {
+	response := `{"op":"REPLY","result":{"type":"105","dest":"VsKV7grR1BUE29mG2Fm2kZ","seqNo":12,"txnTime":1500000000,"data":"{\"dest\":\"VsKV7grR1BUE29mG2Fm2kZ\",\"identifier\":\"Th7MpTaRZVRYnPiabds81Y\",\"role\":\"101\",\"seqNo\":12,\"txnTime\":1500000000,\"verkey\":\"~HFPBKYK5xYpWxhhNQgyLVi\"}"}}`
+
+	nym, err := ParseGetNYMResponse(response)
+	if err != nil {
+		t.Fatalf("Error received from ParseGetNYMResponse: %s", err)
+	}
+	if nym.DID != "VsKV7grR1BUE29mG2Fm2kZ" {
+		t.Fatalf("Unexpected DID [%s]", nym.DID)
+	}
+	if nym.Identifier != "Th7MpTaRZVRYnPiabds81Y" {
+		t.Fatalf("Unexpected identifier [%s]", nym.Identifier)
+	}
+	if nym.Role != "101" {
+		t.Fatalf("Unexpected role [%s]", nym.Role)
+	}
+	if nym.VerKey != "~HFPBKYK5xYpWxhhNQgyLVi" {
+		t.Fatalf("Unexpected verkey [%s]", nym.VerKey)
+	}
+	if nym.SeqNo != 12 || nym.TxnTime != 1500000000 {
+		t.Fatalf("Unexpected seqNo [%d] or txnTime [%d]", nym.SeqNo, nym.TxnTime)
+	}
+
+	_, err = ParseGetNYMResponse(`{"op":"REPLY","result":{"type":"105","dest":"VsKV7grR1BUE29mG2Fm2kZ","data":null}}`)
+	if err != ErrNYMNotFound {
+		t.Fatalf("Expecting error [%s] but got [%v]", ErrNYMNotFound, err)
+	}
+
+	_, err = ParseGetNYMResponse(`{"op":"REQNACK","reason":"client request invalid"}`)
+	if _, ok := err.(*RejectedError); !ok {
+		t.Fatalf("Expecting RejectedError but got [%v]", err)
+	}
+
+	if _, err := ParseGetNYMResponse(`not json`); err == nil {
+		t.Fatalf("Expecting error for invalid response")
+	}
+}
+
+func TestCheckReply(t *testing.T) {
+	if err := CheckReply("NYM", `{"op":"REPLY","result":{}}`); err != nil {
+		t.Fatalf("Error received from CheckReply: %s", err)
+	}
+
+	err := CheckReply("NYM", `{"op":"REJECT","reason":"not authorized"}`)
+	rejected, ok := err.(*RejectedError)
+	if !ok {
+		t.Fatalf("Expecting RejectedError but got [%v]", err)
+	}
+	if rejected.TxnType != "NYM" || rejected.Op != "REJECT" || rejected.Reason != "not authorized" {
+		t.Fatalf("Unexpected RejectedError %+v", rejected)
+	}
+
+	err = CheckReply("NYM", `{`)
+	if _, ok := err.(*RejectedError); ok || err == nil {
+		t.Fatalf("Expecting parse error but got [%v]", err)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/pool"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+const (
+	// UpgradeStart is the POOL_UPGRADE action that schedules an upgrade
+	UpgradeStart = "start"
+
+	// UpgradeCancel is the POOL_UPGRADE action that cancels a scheduled upgrade
+	UpgradeCancel = "cancel"
+
+	// UpgradeTimeFormat is the format of the times in a POOL_UPGRADE schedule
+	UpgradeTimeFormat = "2006-01-02T15:04:05.000000-07:00"
+)
+
+var (
+	versionRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+	sha256Regexp  = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+)
+
+// UpgradeSchedule maps node DIDs to the time at which the node performs the upgrade
+type UpgradeSchedule map[string]time.Time
+
+// NewUpgradeSchedule creates a staggered upgrade schedule for the given nodes.
+// The first node upgrades at start and each subsequent node upgrades interval after
+// the previous one, in the order in which the nodes are given.
+//
+// nodes    The nodes to upgrade, usually pool.Validators of the pool's genesis transactions.
+// start    The upgrade time of the first node.
+// interval The time between the upgrades of two consecutive nodes.
+func NewUpgradeSchedule(nodes []*pool.Node, start time.Time, interval time.Duration) (UpgradeSchedule, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("at least one node must be specified")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be greater than zero")
+	}
+
+	schedule := make(UpgradeSchedule)
+	for i, node := range nodes {
+		if node.DID == "" {
+			return nil, fmt.Errorf("node [%s] has no DID", node.Alias)
+		}
+		if _, ok := schedule[node.DID]; ok {
+			return nil, fmt.Errorf("node [%s] is specified more than once", node.DID)
+		}
+		schedule[node.DID] = start.Add(time.Duration(i) * interval)
+	}
+	return schedule, nil
+}
+
+// JSON returns the schedule in the JSON format expected by the POOL_UPGRADE request
+func (s UpgradeSchedule) JSON() (string, error) {
+	times := make(map[string]string)
+	for did, t := range s {
+		times[did] = t.Format(UpgradeTimeFormat)
+	}
+	bytes, err := json.Marshal(times)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// PoolUpgrade contains the parameters of a POOL_UPGRADE transaction
+type PoolUpgrade struct {
+	// Name is the human-readable name for the upgrade
+	Name string
+	// Version is the version of the indy-node package to upgrade to
+	Version string
+	// SHA256 is the sha256 hash of the package
+	SHA256 string
+	// Timeout limits the upgrade time on each node, in minutes. Zero means no limit.
+	Timeout int
+	// Schedule contains the time at which each node performs the upgrade
+	Schedule UpgradeSchedule
+	// Justification is an optional justification for the upgrade
+	Justification string
+	// Reinstall allows the same version to be re-installed
+	Reinstall bool
+	// Force applies the transaction without waiting for consensus
+	Force bool
+}
+
+// Validate checks that the upgrade may be used to start a pool upgrade at the given time.
+// Each node must be scheduled in the future and, if a timeout is specified, the upgrades
+// of two nodes must be at least the timeout apart so that nodes don't upgrade concurrently.
+func (u *PoolUpgrade) Validate(now time.Time) error {
+	if u.Name == "" {
+		return fmt.Errorf("upgrade name must be specified")
+	}
+	if !versionRegexp.MatchString(u.Version) {
+		return fmt.Errorf("invalid version [%s]", u.Version)
+	}
+	if !sha256Regexp.MatchString(u.SHA256) {
+		return fmt.Errorf("invalid sha256 [%s]: expecting 64 hex characters", u.SHA256)
+	}
+	if u.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if len(u.Schedule) == 0 {
+		return fmt.Errorf("schedule must be specified")
+	}
+
+	var times []time.Time
+	for did, t := range u.Schedule {
+		if !t.After(now) {
+			return fmt.Errorf("upgrade time [%s] of node [%s] is not in the future", t.Format(UpgradeTimeFormat), did)
+		}
+		times = append(times, t)
+	}
+
+	if u.Timeout > 0 {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		timeout := time.Duration(u.Timeout) * time.Minute
+		for i := 1; i < len(times); i++ {
+			if times[i].Sub(times[i-1]) < timeout {
+				return fmt.Errorf("time between upgrades [%s] is less than the timeout [%s]", times[i].Sub(times[i-1]), timeout)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SchedulePoolUpgrade validates the given upgrade and then builds, signs and submits
+// a POOL_UPGRADE request with the 'start' action.
+//
+// pool       The pool.
+// wallet     The wallet holding the Trustee's keys.
+// trusteeDID DID of the Trustee submitting the request.
+// upgrade    The upgrade to schedule.
+func SchedulePoolUpgrade(pool *pool.Pool, wallet *wallet.Wallet, trusteeDID string, upgrade *PoolUpgrade) (responseJSON string, err error) {
+	if err := upgrade.Validate(time.Now()); err != nil {
+		return "", err
+	}
+
+	schedule, err := upgrade.Schedule.JSON()
+	if err != nil {
+		return "", err
+	}
+
+	request, err := BuildPoolUpgradeRequest(trusteeDID, upgrade.Name, upgrade.Version, UpgradeStart, upgrade.SHA256, upgrade.Timeout, schedule, upgrade.Justification, upgrade.Reinstall, upgrade.Force)
+	if err != nil {
+		return "", err
+	}
+
+	return submitUpgrade(pool, wallet, trusteeDID, request)
+}
+
+// CancelPoolUpgrade builds, signs and submits a POOL_UPGRADE request with the 'cancel' action
+// for a previously scheduled upgrade.
+//
+// pool       The pool.
+// wallet     The wallet holding the Trustee's keys.
+// trusteeDID DID of the Trustee submitting the request.
+// upgrade    The upgrade to cancel. Only the name, version and sha256 are used.
+func CancelPoolUpgrade(pool *pool.Pool, wallet *wallet.Wallet, trusteeDID string, upgrade *PoolUpgrade) (responseJSON string, err error) {
+	request, err := BuildPoolUpgradeRequest(trusteeDID, upgrade.Name, upgrade.Version, UpgradeCancel, upgrade.SHA256, 0, "", upgrade.Justification, false, false)
+	if err != nil {
+		return "", err
+	}
+
+	return submitUpgrade(pool, wallet, trusteeDID, request)
+}
+
+func submitUpgrade(pool *pool.Pool, wallet *wallet.Wallet, trusteeDID, request string) (string, error) {
+	response, err := SignAndSubmitRequest(pool, wallet, trusteeDID, request)
+	if err != nil {
+		return "", err
+	}
+
+	if err := CheckReply("POOL_UPGRADE", response); err != nil {
+		return "", err
+	}
+
+	return response, nil
+}
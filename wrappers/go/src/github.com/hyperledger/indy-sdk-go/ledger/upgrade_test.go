@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/pool"
+)
+
+const (
+	genesisFile = "../test/testdata/docker_pool_transactions_genesis"
+	sha256      = "f284bdc3c1c9e24a494e285cb387c69510f28de51c15bb93179d9c7f28705398"
+)
+
+func TestUpgradeSchedule(t *testing.T) {
+	nodes, err := pool.ReadGenesisFile(genesisFile)
+	if err != nil {
+		t.Fatalf("Error received from ReadGenesisFile: %s", err)
+	}
+	validators := pool.Validators(nodes)
+	if len(validators) != 4 {
+		t.Fatalf("Expecting 4 validators but got %d", len(validators))
+	}
+
+	start := time.Now().Add(time.Hour)
+	schedule, err := NewUpgradeSchedule(validators, start, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Error received from NewUpgradeSchedule: %s", err)
+	}
+	for i, node := range validators {
+		expected := start.Add(time.Duration(i) * 10 * time.Minute)
+		if !schedule[node.DID].Equal(expected) {
+			t.Fatalf("Expecting node [%s] to upgrade at [%s] but got [%s]", node.Alias, expected, schedule[node.DID])
+		}
+	}
+
+	upgrade := &PoolUpgrade{
+		Name:     "upgrade-go",
+		Version:  "2.0.0",
+		SHA256:   sha256,
+		Timeout:  10,
+		Schedule: schedule,
+	}
+	if err := upgrade.Validate(time.Now()); err != nil {
+		t.Fatalf("Error received from Validate: %s", err)
+	}
+
+	upgrade.Timeout = 15
+	if err := upgrade.Validate(time.Now()); err == nil {
+		t.Fatalf("Expecting error for timeout greater than the interval")
+	}
+
+	upgrade.Timeout = 10
+	upgrade.SHA256 = "f284b"
+	if err := upgrade.Validate(time.Now()); err == nil {
+		t.Fatalf("Expecting error for invalid sha256")
+	}
+
+	upgrade.SHA256 = sha256
+	if err := upgrade.Validate(start.Add(time.Hour)); err == nil {
+		t.Fatalf("Expecting error for upgrade time in the past")
+	}
+
+	scheduleJSON, err := schedule.JSON()
+	if err != nil {
+		t.Fatalf("Error received from JSON: %s", err)
+	}
+	t.Logf("Schedule: %s", scheduleJSON)
+}
+
+func TestBuildPoolUpgradeRequest(t *testing.T) {
+	req, err := BuildPoolUpgradeRequest(did1, "upgrade-go", "2.0.0", UpgradeCancel, sha256, 0, "", "", false, false)
+	if err != nil {
+		t.Fatalf("Error received from BuildPoolUpgradeRequest: %s", err)
+	}
+	t.Logf("Pool upgrade request created: %s", req)
+}
@@ -0,0 +1,313 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pairwise
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/common/callback"
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/indy"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+// Pairwise is a pairwise relationship between one of my DIDs and a DID of another party
+type Pairwise struct {
+	// MyDID is my DID of the relationship
+	MyDID string `json:"my_did"`
+
+	// TheirDID is the DID of the other party
+	TheirDID string `json:"their_did"`
+
+	// Metadata is the metadata stored with the pairwise. Empty if no metadata is stored.
+	// Metadata set with Create or SetMetadata is json and may be decoded with UnmarshalMetadata.
+	Metadata string `json:"metadata,omitempty"`
+}
+
+// UnmarshalMetadata decodes the json metadata of the pairwise into the given value
+func (p *Pairwise) UnmarshalMetadata(v interface{}) error {
+	if p.Metadata == "" {
+		return fmt.Errorf("pairwise of DID [%s] has no metadata", p.TheirDID)
+	}
+	return json.Unmarshal([]byte(p.Metadata), v)
+}
+
+// Create creates a pairwise relationship between my DID and their DID in the wallet.
+// Their DID must have been stored with did.StoreTheirDID.
+//
+// wallet   The wallet.
+// theirDID The DID of the other party.
+// myDID    My DID, created with did.CreateAndStoreMyDID.
+// metadata Optional metadata. Any value that can be marshaled to json, or nil.
+func Create(wallet *wallet.Wallet, theirDID, myDID string, metadata interface{}) error {
+	return <-create(wallet, theirDID, myDID, metadata)
+}
+
+// Exists returns true if a pairwise relationship with their DID exists in the wallet.
+//
+// wallet   The wallet.
+// theirDID The DID of the other party.
+func Exists(wallet *wallet.Wallet, theirDID string) (exists bool, err error) {
+	existsChan, errChan := isExists(wallet, theirDID)
+	select {
+	case exists = <-existsChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// Get returns the pairwise relationship with their DID.
+// An error with code WalletNotFoundError is returned if there is no relationship with their DID.
+//
+// wallet   The wallet.
+// theirDID The DID of the other party.
+func Get(wallet *wallet.Wallet, theirDID string) (pairwise *Pairwise, err error) {
+	pairwiseChan, errChan := get(wallet, theirDID)
+	select {
+	case pairwise = <-pairwiseChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// List returns all pairwise relationships stored in the wallet.
+//
+// wallet The wallet.
+func List(wallet *wallet.Wallet) (pairwise []*Pairwise, err error) {
+	listChan, errChan := list(wallet)
+	select {
+	case pairwise = <-listChan:
+	case err = <-errChan:
+	}
+	return
+}
+
+// SetMetadata saves the metadata of the pairwise relationship with their DID, replacing any
+// existing metadata. The metadata is stored as json.
+//
+// wallet   The wallet.
+// theirDID The DID of the other party.
+// metadata The metadata. Any value that can be marshaled to json, or nil to remove the metadata.
+func SetMetadata(wallet *wallet.Wallet, theirDID string, metadata interface{}) error {
+	return <-setMetadata(wallet, theirDID, metadata)
+}
+
+func create(wallet *wallet.Wallet, theirDID, myDID string, metadata interface{}) chan error {
+	logger.Debugf("Creating pairwise - Wallet [%s], TheirDID [%s], MyDID [%s]", wallet.Name, theirDID, myDID)
+
+	errChan := make(chan error, 1)
+
+	if theirDID == "" {
+		errChan <- fmt.Errorf("their DID must be specified")
+		return errChan
+	}
+	if myDID == "" {
+		errChan <- fmt.Errorf("my DID must be specified")
+		return errChan
+	}
+
+	metadataJSON, err := marshalMetadata(metadata)
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.CreatePairwise(walletHandle, theirDID, myDID, metadataJSON, callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
+
+func isExists(wallet *wallet.Wallet, theirDID string) (chan bool, chan error) {
+	logger.Debugf("Checking if pairwise exists - Wallet [%s], TheirDID [%s]", wallet.Name, theirDID)
+
+	existsChan := make(chan bool)
+	errChan := make(chan error, 1)
+
+	if theirDID == "" {
+		errChan <- fmt.Errorf("their DID must be specified")
+		return existsChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			existsChan <- data.(bool)
+		}
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return existsChan, errChan
+	}
+
+	err = indy.IsPairwiseExists(walletHandle, theirDID, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return existsChan, errChan
+}
+
+func get(wallet *wallet.Wallet, theirDID string) (chan *Pairwise, chan error) {
+	logger.Debugf("Getting pairwise - Wallet [%s], TheirDID [%s]", wallet.Name, theirDID)
+
+	pairwiseChan := make(chan *Pairwise)
+	errChan := make(chan error, 1)
+
+	if theirDID == "" {
+		errChan <- fmt.Errorf("their DID must be specified")
+		return pairwiseChan, errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+			return
+		}
+		pairwise, err := asPairwise(theirDID, data.(string))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		pairwiseChan <- pairwise
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return pairwiseChan, errChan
+	}
+
+	err = indy.GetPairwise(walletHandle, theirDID, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return pairwiseChan, errChan
+}
+
+func list(wallet *wallet.Wallet) (chan []*Pairwise, chan error) {
+	logger.Debugf("Listing pairwise - Wallet [%s]", wallet.Name)
+
+	listChan := make(chan []*Pairwise)
+	errChan := make(chan error, 1)
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+			return
+		}
+		pairwise, err := asPairwiseList(data.(string))
+		if err != nil {
+			errChan <- err
+			return
+		}
+		listChan <- pairwise
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return listChan, errChan
+	}
+
+	err = indy.ListPairwise(walletHandle, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return listChan, errChan
+}
+
+func setMetadata(wallet *wallet.Wallet, theirDID string, metadata interface{}) chan error {
+	logger.Debugf("Setting pairwise metadata - Wallet [%s], TheirDID [%s]", wallet.Name, theirDID)
+
+	errChan := make(chan error, 1)
+
+	if theirDID == "" {
+		errChan <- fmt.Errorf("their DID must be specified")
+		return errChan
+	}
+
+	metadataJSON, err := marshalMetadata(metadata)
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	walletHandle, err := wallet.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.SetPairwiseMetadata(walletHandle, theirDID, metadataJSON, callback.New(errChan))
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return errChan
+}
+
+// marshalMetadata returns the json of the metadata or an empty string for nil metadata
+func marshalMetadata(metadata interface{}) (string, error) {
+	if metadata == nil {
+		return "", nil
+	}
+	bytes, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling pairwise metadata: %s", err)
+	}
+	return string(bytes), nil
+}
+
+// asPairwise parses the pairwise info json returned by libindy, which doesn't include their DID
+func asPairwise(theirDID, pairwiseJSON string) (*Pairwise, error) {
+	pairwise := &Pairwise{}
+	if err := json.Unmarshal([]byte(pairwiseJSON), pairwise); err != nil {
+		return nil, fmt.Errorf("invalid pairwise json: %s", err)
+	}
+	pairwise.TheirDID = theirDID
+	return pairwise, nil
+}
+
+// asPairwiseList parses the pairwise list returned by libindy, which is a json array of pairwise json strings
+func asPairwiseList(listJSON string) ([]*Pairwise, error) {
+	var list []string
+	if err := json.Unmarshal([]byte(listJSON), &list); err != nil {
+		return nil, fmt.Errorf("invalid pairwise list json: %s", err)
+	}
+
+	pairwise := make([]*Pairwise, len(list))
+	for i, pairwiseJSON := range list {
+		p := &Pairwise{}
+		if err := json.Unmarshal([]byte(pairwiseJSON), p); err != nil {
+			return nil, fmt.Errorf("invalid pairwise json: %s", err)
+		}
+		pairwise[i] = p
+	}
+	return pairwise, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pairwise
+
+import (
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/did"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+type connection struct {
+	Label string `json:"label"`
+	State string `json:"state"`
+}
+
+func TestPairwise(t *testing.T) {
+	w, err := getWallet("pairwise_wallet1", "pool1")
+	if err != nil {
+		t.Fatalf("error getting wallet: %s", err)
+	}
+	defer w.Close()
+
+	myInfo, err := did.CreateAndStoreMyDID(w, "{}")
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+	theirInfo, err := did.CreateAndStoreMyDID(w, "{}")
+	if err != nil {
+		t.Fatalf("Error received from CreateAndStoreMyDID: %s", err)
+	}
+	if err := did.StoreTheirDID(w, &did.TheirDIDInfo{DID: theirInfo.DID, VerKey: theirInfo.VerKey}); err != nil {
+		t.Fatalf("Error received from StoreTheirDID: %s", err)
+	}
+
+	exists, err := Exists(w, theirInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from Exists: %s", err)
+	}
+	if exists {
+		t.Fatalf("Expecting pairwise not to exist")
+	}
+
+	if err := Create(w, theirInfo.DID, myInfo.DID, &connection{Label: "Faber", State: "requested"}); err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+
+	exists, err = Exists(w, theirInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from Exists: %s", err)
+	}
+	if !exists {
+		t.Fatalf("Expecting pairwise to exist")
+	}
+
+	if err := SetMetadata(w, theirInfo.DID, &connection{Label: "Faber", State: "complete"}); err != nil {
+		t.Fatalf("Error received from SetMetadata: %s", err)
+	}
+
+	pairwise, err := Get(w, theirInfo.DID)
+	if err != nil {
+		t.Fatalf("Error received from Get: %s", err)
+	}
+	if pairwise.MyDID != myInfo.DID || pairwise.TheirDID != theirInfo.DID {
+		t.Fatalf("Unexpected pairwise %+v", pairwise)
+	}
+	c := &connection{}
+	if err := pairwise.UnmarshalMetadata(c); err != nil {
+		t.Fatalf("Error received from UnmarshalMetadata: %s", err)
+	}
+	if c.State != "complete" {
+		t.Fatalf("Expecting state [complete] but got [%s]", c.State)
+	}
+
+	list, err := List(w)
+	if err != nil {
+		t.Fatalf("Error received from List: %s", err)
+	}
+	found := false
+	for _, p := range list {
+		if p.TheirDID == theirInfo.DID && p.MyDID == myInfo.DID {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expecting pairwise for [%s] in list", theirInfo.DID)
+	}
+
+	if _, err := Get(w, myInfo.DID); indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletNotFoundError), err)
+	}
+}
+
+func TestAsPairwiseList(t *testing.T) {
+	list, err := asPairwiseList(`["{\"my_did\":\"VsKV7grR1BUE29mG2Fm2kX\",\"their_did\":\"V4SGRU86Z58d6TV7PBUe6f\",\"metadata\":\"{\\\"label\\\":\\\"Faber\\\"}\"}","{\"my_did\":\"VsKV7grR1BUE29mG2Fm2kX\",\"their_did\":\"Th7MpTaRZVRYnPiabds81Y\"}"]`)
+	if err != nil {
+		t.Fatalf("Error received from asPairwiseList: %s", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Expecting 2 pairwise but got %d", len(list))
+	}
+	if list[0].TheirDID != "V4SGRU86Z58d6TV7PBUe6f" || list[1].TheirDID != "Th7MpTaRZVRYnPiabds81Y" {
+		t.Fatalf("Unexpected pairwise list %+v, %+v", list[0], list[1])
+	}
+
+	c := &connection{}
+	if err := list[0].UnmarshalMetadata(c); err != nil {
+		t.Fatalf("Error received from UnmarshalMetadata: %s", err)
+	}
+	if c.Label != "Faber" {
+		t.Fatalf("Expecting label [Faber] but got [%s]", c.Label)
+	}
+	if err := list[1].UnmarshalMetadata(c); err == nil {
+		t.Fatalf("Expecting error for pairwise without metadata")
+	}
+
+	pairwise, err := asPairwise("V4SGRU86Z58d6TV7PBUe6f", `{"my_did":"VsKV7grR1BUE29mG2Fm2kX"}`)
+	if err != nil {
+		t.Fatalf("Error received from asPairwise: %s", err)
+	}
+	if pairwise.TheirDID != "V4SGRU86Z58d6TV7PBUe6f" {
+		t.Fatalf("Expecting their DID to be set but got [%s]", pairwise.TheirDID)
+	}
+}
+
+func getWallet(walletName, poolName string) (*wallet.Wallet, error) {
+	err := wallet.Create(poolName, walletName, "", "", "")
+	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		return nil, err
+	}
+	return wallet.Open(walletName, "", "")
+}
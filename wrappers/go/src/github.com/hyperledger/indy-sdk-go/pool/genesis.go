@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pool
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// nodeTxnType is the transaction type of a NODE transaction
+	nodeTxnType = "0"
+
+	// ValidatorService is the service provided by validator nodes
+	ValidatorService = "VALIDATOR"
+)
+
+// Node is a node of the pool as defined by the NODE transactions of the pool ledger
+type Node struct {
+	DID      string
+	Alias    string
+	Services []string
+}
+
+// IsValidator returns true if the node provides the VALIDATOR service
+func (n *Node) IsValidator() bool {
+	for _, service := range n.Services {
+		if service == ValidatorService {
+			return true
+		}
+	}
+	return false
+}
+
+type nodeTxn struct {
+	Type string `json:"type"`
+	Dest string `json:"dest"`
+	Data struct {
+		Alias    string    `json:"alias"`
+		Services *[]string `json:"services"`
+	} `json:"data"`
+}
+
+// ReadGenesis reads the NODE transactions from the given genesis transactions
+// (one JSON transaction per line) and returns the nodes of the pool in the order
+// in which they were first added. A later transaction for the same node updates
+// the alias and services of that node.
+func ReadGenesis(r io.Reader) ([]*Node, error) {
+	var nodes []*Node
+	nodesByDID := make(map[string]*Node)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var txn nodeTxn
+		if err := json.Unmarshal([]byte(text), &txn); err != nil {
+			return nil, fmt.Errorf("invalid genesis transaction on line %d: %s", line, err)
+		}
+		if txn.Type != nodeTxnType {
+			continue
+		}
+		if txn.Dest == "" {
+			return nil, fmt.Errorf("NODE transaction on line %d has no dest", line)
+		}
+
+		node, ok := nodesByDID[txn.Dest]
+		if !ok {
+			node = &Node{DID: txn.Dest}
+			nodesByDID[txn.Dest] = node
+			nodes = append(nodes, node)
+		}
+		if txn.Data.Alias != "" {
+			node.Alias = txn.Data.Alias
+		}
+		if txn.Data.Services != nil {
+			node.Services = *txn.Data.Services
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// ReadGenesisFile reads the nodes of the pool from the given genesis transactions file.
+func ReadGenesisFile(path string) ([]*Node, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadGenesis(f)
+}
+
+// Validators returns the nodes that provide the VALIDATOR service.
+func Validators(nodes []*Node) []*Node {
+	var validators []*Node
+	for _, node := range nodes {
+		if node.IsValidator() {
+			validators = append(validators, node)
+		}
+	}
+	return validators
+}
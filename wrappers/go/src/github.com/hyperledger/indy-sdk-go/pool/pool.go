@@ -9,6 +9,9 @@ package pool
 import (
 	"encoding/json"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hyperledger/indy-sdk-go/common/callback"
 	"github.com/hyperledger/indy-sdk-go/common/logging"
@@ -18,17 +21,58 @@ import (
 
 var logger = logging.MustGetLogger("indy-sdk")
 
+// ErrClosed is returned when a pool is used after it was closed
+var ErrClosed = fmt.Errorf("pool is closed")
+
+// warnUnclosed is set to 1 if a warning should be logged for pools that are garbage collected while open
+var warnUnclosed int32
+
 // Pool is the Pool Ledger
 type Pool struct {
 	Name   string `json:"pool"`
 	handle types.Handle
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// SetFinalizerWarnings enables or disables a warning that is logged when a pool is garbage
+// collected without being closed. The setting applies to pools opened subsequently.
+func SetFinalizerWarnings(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&warnUnclosed, 1)
+	} else {
+		atomic.StoreInt32(&warnUnclosed, 0)
+	}
 }
 
-// Handle returns the Indy handle of the pool
+// Handle returns the Indy handle of the pool. The handle is returned even if the
+// pool is closed; use OpenHandle to check that the pool is still open.
 func (p *Pool) Handle() types.Handle {
 	return p.handle
 }
 
+// OpenHandle returns the Indy handle of the pool or ErrClosed if the pool is closed
+func (p *Pool) OpenHandle() (types.Handle, error) {
+	if p == nil {
+		return 0, fmt.Errorf("pool must be specified")
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.closed {
+		return 0, ErrClosed
+	}
+	return p.handle, nil
+}
+
+// IsClosed returns true if the pool was closed
+func (p *Pool) IsClosed() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.closed
+}
+
 // Create creates a new local pool ledger configuration that can be used later to connect pool nodes.
 //
 // configName Name of the pool ledger configuration.
@@ -75,6 +119,7 @@ func (p *Pool) Refresh() error {
 }
 
 // Close closes opened pool ledger, opened nodes connections and frees allocated resources.
+// Closing a closed pool has no effect.
 func (p *Pool) Close() error {
 	return <-p.close()
 }
@@ -136,11 +181,14 @@ func open(name string, config string) (chan *Pool, chan error) {
 		if err != nil {
 			errChan <- err
 		} else {
-			handle := data.(types.Handle)
-			poolChan <- &Pool{
+			p := &Pool{
 				Name:   name,
-				handle: handle,
+				handle: data.(types.Handle),
+			}
+			if atomic.LoadInt32(&warnUnclosed) == 1 {
+				runtime.SetFinalizer(p, warnIfOpen)
 			}
+			poolChan <- p
 		}
 	}
 
@@ -188,7 +236,14 @@ func (p *Pool) refresh() chan error {
 	logger.Debugf("Refreshing pool [%s]...", p.Name)
 
 	errChan := make(chan error, 1)
-	err := indy.RefreshPoolLedger(p.handle, callback.New(errChan))
+
+	handle, err := p.OpenHandle()
+	if err != nil {
+		errChan <- err
+		return errChan
+	}
+
+	err = indy.RefreshPoolLedger(handle, callback.New(errChan))
 	if err != nil {
 		// Send the error immediately
 		errChan <- err
@@ -201,17 +256,51 @@ func (p *Pool) close() chan error {
 	logger.Debugf("Closing pool [%s]...", p.Name)
 
 	errChan := make(chan error, 1)
-	err := indy.ClosePoolLedger(p.handle, callback.New(errChan))
+
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		errChan <- nil
+		return errChan
+	}
+	p.closed = true
+	p.mutex.Unlock()
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			logger.Debugf("Error closing pool [%s]: %s", p.Name, err)
+			p.reopen()
+		}
+		errChan <- err
+	}
+
+	err := indy.ClosePoolLedger(p.handle, cb)
 	if err != nil {
 		// Send the error immediately
+		p.reopen()
 		errChan <- err
 	}
 
 	return errChan
 }
 
+// reopen marks the pool as open after closing it failed
+func (p *Pool) reopen() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = false
+}
+
+func warnIfOpen(p *Pool) {
+	if !p.IsClosed() {
+		logger.Warnf("Pool [%s] was garbage collected without being closed", p.Name)
+	}
+}
+
 func asPools(poolsJSON string) ([]string, error) {
-	var pools []Pool
+	var pools []struct {
+		Name string `json:"pool"`
+	}
 	if err := json.Unmarshal([]byte(poolsJSON), &pools); err != nil {
 		return nil, err
 	}
@@ -54,3 +54,17 @@ func TestPool(t *testing.T) {
 		t.Logf("Success received from Delete")
 	}
 }
+
+func TestClosedPool(t *testing.T) {
+	p := &Pool{Name: "pool1", handle: 1, closed: true}
+
+	if _, err := p.OpenHandle(); err != ErrClosed {
+		t.Fatalf("Expecting error [%s] but got [%v]", ErrClosed, err)
+	}
+	if err := p.Refresh(); err != ErrClosed {
+		t.Fatalf("Expecting error [%s] from Refresh but got [%v]", ErrClosed, err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Expecting Close of closed pool to succeed but got [%v]", err)
+	}
+}
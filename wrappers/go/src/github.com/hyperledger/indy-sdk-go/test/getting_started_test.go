@@ -11,15 +11,17 @@ import (
 	"testing"
 
 	"github.com/hyperledger/indy-sdk-go/anoncreds"
-	"github.com/hyperledger/indy-sdk-go/common/indyerror"
 	"github.com/hyperledger/indy-sdk-go/common/role"
+	"github.com/hyperledger/indy-sdk-go/connection"
 	"github.com/hyperledger/indy-sdk-go/crypto"
 	"github.com/hyperledger/indy-sdk-go/did"
+	"github.com/hyperledger/indy-sdk-go/did/util"
 	"github.com/hyperledger/indy-sdk-go/ledger"
 	"github.com/hyperledger/indy-sdk-go/pool"
 	"github.com/hyperledger/indy-sdk-go/test/assert"
 	"github.com/hyperledger/indy-sdk-go/test/json"
 	"github.com/hyperledger/indy-sdk-go/wallet"
+	"github.com/hyperledger/indy-sdk-go/wallet/inmem"
 )
 
 var poolConfig = `{"genesis_txn": "./testdata/docker_pool_transactions_genesis"}`
@@ -182,7 +184,7 @@ func TestGettingStarted(t *testing.T) {
 
 	fmt.Println(`"Faber" -> Get key for Alice did`)
 	aliceFaberVerKey, err := did.KeyForDID(p, acmeWallet, faberAliceConnectionResponse["did"].String())
-	assert.NoErrorf(t, err, "Error received from KeyForDID - DID [%s], Wallet [%s]: %s", faberAliceConnectionResponse["did"].String(), acmeWallet.Name)
+	assert.NoErrorf(t, err, "Error received from KeyForDID - DID [%s], Wallet [%s]: %s", faberAliceConnectionResponse["did"].String(), acmeWallet.Name, err)
 
 	fmt.Println(`"Faber" -> Authcrypt "Transcript" Credential Offer for Alice`)
 	authCryptedTranscriptCredOffer, err := crypto.AuthCrypt(faberWallet, faberAliceDIDInfo.VerKey, aliceFaberVerKey, []byte(transcriptCredOfferJSON))
@@ -645,6 +647,30 @@ func TestGettingStarted(t *testing.T) {
 	assert.Equalf(t, true, valid, `"Thrift" -> Verify "Loan-Application-KYC" Proof from Alice is NOT valid`)
 	fmt.Printf(`"Thrift" -> Verify "Loan-Application-KYC" Proof from Alice is valid!` + "\n")
 
+	fmt.Printf("==============================" + "\n")
+	fmt.Printf("=== Key Rotation ==" + "\n")
+	fmt.Printf("------------------------------" + "\n")
+
+	fmt.Printf(`"Thrift" -> Rotate key of Thrift DID` + "\n")
+	thriftNewVerKey, err := did.RotateKey(p, thriftWallet, thriftDIDInfo.DID)
+	assert.NoError(t, err)
+
+	fmt.Printf(`"Thrift" -> Get key of Thrift DID from Wallet` + "\n")
+	thriftWalletVerKey, err := did.KeyForLocalDID(thriftWallet, thriftDIDInfo.DID)
+	assert.NoError(t, err)
+	assert.Equalf(t, thriftNewVerKey, thriftWalletVerKey, "New key of Thrift DID isn't applied in the wallet")
+
+	fmt.Printf(`"Thrift" -> Get key of Thrift DID from Ledger` + "\n")
+	thriftNYM, err := ledger.GetNYM(p, thriftDIDInfo.DID, thriftDIDInfo.DID)
+	assert.NoError(t, err)
+	thriftLedgerVerKey, err := util.ExpandVerKey(thriftDIDInfo.DID, thriftNYM.VerKey)
+	assert.NoError(t, err)
+	assert.Equalf(t, thriftNewVerKey, thriftLedgerVerKey, "New key of Thrift DID isn't on the ledger")
+
+	fmt.Printf(`"Thrift" -> Rotate key of Thrift DID again, signed with the new key` + "\n")
+	_, err = did.RotateKey(p, thriftWallet, thriftDIDInfo.DID)
+	assert.NoError(t, err)
+
 	fmt.Printf("==============================" + "\n")
 
 	fmt.Printf(`"Sovrin Steward" -> Close and Delete wallet` + "\n")
@@ -683,19 +709,11 @@ func onboarding(
 	from string, fromWallet *wallet.Wallet, fromDID string,
 	to string, toWallet *wallet.Wallet, toWalletName string) (toWalletRet *wallet.Wallet, fromToDIDInfo *did.Info, toFromDID *did.Info, decryptedConnectionResponseJSON string, err error) {
 
-	fmt.Printf(`"%s" -> Create and store in Wallet "{%s} {%s}" DID`+"\n", from, from, to)
-
-	fromToDIDInfo, err = did.CreateAndStoreMyDID(fromWallet, "{}")
-	if err != nil {
-		err = fmt.Errorf("error creating and storing DID - Wallet [%s]: %s", fromWallet.Name, err)
-		return
-	}
-
-	fmt.Printf(`"%s" -> Send Nym to Ledger for "{%s} {%s}" DID`+"\n", from, from, to)
-
-	_, err = sendNYM(pool, fromWallet, fromDID, fromToDIDInfo.DID, fromToDIDInfo.VerKey, nil)
+	fmt.Printf(`"%s" -> Create and store in Wallet "{%s} {%s}" DID and send Nym to Ledger`+"\n", from, from, to)
+	inviter := connection.NewInviter(fromWallet, pool, fromDID)
+	request, err := inviter.CreateRequest()
 	if err != nil {
-		err = fmt.Errorf("error sending NYM - Wallet [%s], FromDID [%s], FromToDID [%s], FromToVerKey [%s]: %s", fromWallet.Name, fromDID, fromToDIDInfo.DID, fromToDIDInfo.VerKey, err)
+		err = fmt.Errorf("error creating connection request - Wallet [%s], FromDID [%s]: %s", fromWallet.Name, fromDID, err)
 		return
 	}
 
@@ -709,66 +727,28 @@ func onboarding(
 		}
 	}
 
-	fmt.Printf(`"%s" -> Create and store in Wallet "%s %s" DID`+"\n", to, to, from)
-	toFromDID, err = did.CreateAndStoreMyDID(toWalletRet, "{}")
-	if err != nil {
-		err = fmt.Errorf("error creating and storing DID - Wallet [%s]: %s", toWalletRet.Name, err)
-		return
-	}
-
-	fmt.Printf(`"%s" -> Send connection request to %s with "%s %s" DID and nonce`+"\n", from, to, from, to)
-	connectionRequest := json.Map{
-		"did":   json.Str(fromToDIDInfo.DID),
-		"nonce": json.Int(123456789),
-	}
-
-	// (If this were real then the connection request would encrypted and sent to the "to" endpoint)
+	// (If this were real then the connection request would be sent to the "to" endpoint)
 
-	// The "to" endpoint receives the connection request and sends back an encrypted response...
-	fmt.Printf(`"%s" -> Get key for did from "%s" connection request`+"\n", to, from)
-	var fromToVerKey string
-	fromToVerKey, err = did.KeyForDID(pool, toWalletRet, fromToDIDInfo.DID)
+	fmt.Printf(`"%s" -> Create and store in Wallet "%s %s" DID and anoncrypt connection response`+"\n", to, to, from)
+	encryptedResponse, toConnection, err := connection.NewInvitee(toWalletRet, pool).Accept(request)
 	if err != nil {
-		err = fmt.Errorf("error getting key for DID - DID [%s], Wallet [%s]: %s", fromToDIDInfo.DID, toWalletRet.Name, err)
+		err = fmt.Errorf("error accepting connection request - Wallet [%s]: %s", toWalletRet.Name, err)
 		return
 	}
-	fmt.Printf(`"%s" -> Anoncrypt connection response for "%s" with "%s %s" DID, verkey and nonce`+"\n", to, from, to, from)
-	connectionResponseJSON := json.Map{
-		"did":    json.Str(toFromDID.DID),
-		"verKey": json.Str(toFromDID.VerKey),
-		"nonce":  json.Int(connectionRequest["nonce"].Int64()),
-	}.JSON()
 
-	var anoncryptedConnectionResponse []byte
-	anoncryptedConnectionResponse, err = crypto.AnonCrypt(fromToVerKey, []byte(connectionResponseJSON))
+	fmt.Printf(`"%s" -> Anondecrypt connection response from "%s", authenticate by nonce and send Nym to Ledger`+"\n", from, to)
+	fromConnection, err := inviter.AcceptResponse(request, encryptedResponse)
 	if err != nil {
-		err = fmt.Errorf("error encrypting connectionResponse [%s]: %s", connectionResponseJSON, err)
+		err = fmt.Errorf("error accepting connection response - Wallet [%s]: %s", fromWallet.Name, err)
 		return
 	}
-	fmt.Printf(`"%s" -> Send anoncrypted connection response to "%s"`+"\n", to, from)
 
-	// The "from" endpoint receives the connection response, decrypts it, and authenticates it (using the nonce)
-	fmt.Printf(`"%s" -> Anondecrypt connection response from "%s"`+"\n", from, to)
-	decryptedConnectionResponseJSONBytes, err := crypto.AnonDecrypt(fromWallet, fromToDIDInfo.VerKey, anoncryptedConnectionResponse)
-	if err != nil {
-		err = fmt.Errorf("error decrypting connectionResponse: %s", err)
-		return
-	}
-	decryptedConnectionResponseJSON = string(decryptedConnectionResponseJSONBytes)
-	decryptedConnectionResponse := json.AsMap(decryptedConnectionResponseJSON)
-
-	fmt.Printf(`"%s" -> Authenticates "%s" by comparison of Nonce`+"\n", from, to)
-	if decryptedConnectionResponse["nonce"].Int64() != connectionRequest["nonce"].Int64() {
-		err = fmt.Errorf("Expecting decrypted connection response nonce to be %d but got %d", connectionRequest["nonce"].Int64(), decryptedConnectionResponse["nonce"].Int64())
-		return
-	}
-
-	fmt.Printf(`"%s" -> Send Nym to Ledger for "%s %s" DID`+"\n", from, to, from)
-	_, err = sendNYM(pool, fromWallet, fromDID, toFromDID.DID, toFromDID.VerKey, nil)
-	if err != nil {
-		err = fmt.Errorf("error sending NYM - FromWallet [%s], FromDID [%s], ToFromDID [%s], ToFromVerKey [%s]: %s", fromWallet.Name, fromDID, toFromDID.DID, toFromDID.VerKey, err)
-		return
-	}
+	fromToDIDInfo = &did.Info{DID: fromConnection.MyDID, VerKey: fromConnection.MyVerKey}
+	toFromDID = &did.Info{DID: toConnection.MyDID, VerKey: toConnection.MyVerKey}
+	decryptedConnectionResponseJSON = json.Map{
+		"did":    json.Str(fromConnection.TheirDID),
+		"verkey": json.Str(fromConnection.TheirVerKey),
+	}.JSON()
 	return
 }
 
@@ -781,8 +761,23 @@ func sendNYM(pool *pool.Pool, wallet *wallet.Wallet, did string, newDID string,
 }
 
 func getWallet(poolName, walletName string) (*wallet.Wallet, error) {
-	err := wallet.Create(poolName, walletName, "", "", "")
-	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+	if err := inmem.Register(); err != nil {
+		return nil, fmt.Errorf("error registering in-memory wallet type: %s", err)
+	}
+
+	// Delete any wallet that was created by a previous test. libindy keeps the wallet
+	// on disk even though the records of an in-memory wallet are gone.
+	exists, err := wallet.Exists(walletName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking wallet - Wallet [%s]: %s", walletName, err)
+	}
+	if exists {
+		if err := wallet.Delete(walletName, ""); err != nil {
+			return nil, fmt.Errorf("error deleting wallet - Wallet [%s]: %s", walletName, err)
+		}
+	}
+
+	if err := wallet.Create(poolName, walletName, inmem.TypeName, "", ""); err != nil {
 		return nil, fmt.Errorf("error creating wallet - Wallet [%s]: %s", walletName, err)
 	}
 	return wallet.Open(walletName, "", "")
@@ -0,0 +1,262 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+	"github.com/hyperledger/indy-sdk-go/wallet/internal/pbkdf2"
+	"github.com/hyperledger/indy-sdk-go/wallet/migrate"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+// An archive consists of a header followed by the encrypted manifest and records:
+//
+//   header:  magic[8] | version uint16 | iterations uint32 | salt[16] | nonce[12]
+//   body:    AES-256-GCM(json payload), authenticated together with the header
+//
+// All integers are big-endian. The key is derived from the passphrase with PBKDF2-SHA256.
+
+// Version is the version of the archives written by Export
+const Version = 1
+
+const (
+	saltSize   = 16
+	keySize    = 32
+	iterations = 100000
+
+	// maxIterations protects against archives that are expensive to open
+	maxIterations = 100 * iterations
+
+	// maxArchiveSize protects against reading arbitrarily large input
+	maxArchiveSize = 1024 * 1024 * 1024
+)
+
+var magic = []byte("INDYBAK\x00")
+
+// ErrIntegrity is returned by Import if the archive was modified or the passphrase is wrong
+var ErrIntegrity = fmt.Errorf("wallet archive is corrupted or the passphrase is wrong")
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	// Version is the version of the archive format
+	Version int `json:"version"`
+
+	// Wallet is the name of the exported wallet
+	Wallet string `json:"wallet"`
+
+	// PoolName is the name of the pool of the exported wallet
+	PoolName string `json:"pool"`
+
+	// Type is the name of the wallet type of the exported wallet
+	Type string `json:"type"`
+
+	// Created is the time of the export
+	Created time.Time `json:"created"`
+
+	// Records is the number of records in the archive
+	Records int `json:"records"`
+
+	// Checksum is the checksum of the records (see migrate.Checksum)
+	Checksum string `json:"checksum"`
+}
+
+type payload struct {
+	Manifest *Manifest       `json:"manifest"`
+	Records  []wallet.Record `json:"records"`
+}
+
+// Export writes an encrypted archive of all records of the open wallet to the writer. The wallet
+// must be of a wallet type registered with wallet.RegisterType (see wallet.Wallet.Storage).
+// The archive is encrypted with a key derived from the passphrase.
+func Export(w *wallet.Wallet, writer io.Writer, passphrase string) (*Manifest, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase must be specified")
+	}
+
+	storage, err := w.Storage()
+	if err != nil {
+		return nil, err
+	}
+	info, err := lookup(w.Name)
+	if err != nil {
+		return nil, err
+	}
+	records, err := storage.List("")
+	if err != nil {
+		return nil, fmt.Errorf("error reading wallet [%s]: %s", w.Name, err)
+	}
+	if records == nil {
+		records = []wallet.Record{}
+	}
+
+	manifest := &Manifest{
+		Version:  Version,
+		Wallet:   w.Name,
+		PoolName: info.PoolName,
+		Type:     info.Type,
+		Created:  time.Now().UTC(),
+		Records:  len(records),
+		Checksum: migrate.Checksum(records),
+	}
+	if err := write(writer, passphrase, &payload{Manifest: manifest, Records: records}); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Exported %d records of wallet [%s]", manifest.Records, w.Name)
+	return manifest, nil
+}
+
+// write encrypts the payload and writes the archive to the writer
+func write(writer io.Writer, passphrase string, p *payload) error {
+	plain, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := newAEAD(passphrase, salt, iterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	var header bytes.Buffer
+	header.Write(magic)
+	binary.Write(&header, binary.BigEndian, uint16(Version))
+	binary.Write(&header, binary.BigEndian, uint32(iterations))
+	header.Write(salt)
+	header.Write(nonce)
+
+	sealed := aead.Seal(nil, nonce, plain, header.Bytes())
+	if _, err := writer.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err = writer.Write(sealed)
+	return err
+}
+
+// Import reads an archive written by Export and restores the exported wallet with wallet.Create,
+// using the name, pool and wallet type in the manifest of the archive, and the given wallet
+// configuration and credentials json. The wallet type must be registered and the wallet must
+// not exist. The archive is verified before the wallet is created and the restored wallet is
+// verified to contain all records of the archive; if verification fails the wallet is deleted.
+// The restored wallet is closed and may be opened with wallet.Open.
+func Import(r io.Reader, passphrase, config, credentials string) (*Manifest, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase must be specified")
+	}
+
+	manifest, records, err := Read(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &migrate.Endpoint{
+		Name:        manifest.Wallet,
+		PoolName:    manifest.PoolName,
+		Type:        manifest.Type,
+		Config:      config,
+		Credentials: credentials,
+	}
+	if _, err := migrate.Write(target, records); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Imported %d records of wallet [%s]", manifest.Records, manifest.Wallet)
+	return manifest, nil
+}
+
+// Read decrypts and verifies an archive written by Export and returns its manifest and records
+func Read(r io.Reader, passphrase string) (*Manifest, []wallet.Record, error) {
+	archive, err := ioutil.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(archive) > maxArchiveSize {
+		return nil, nil, fmt.Errorf("wallet archive is too large")
+	}
+
+	headerSize := len(magic) + 2 + 4 + saltSize
+	if len(archive) < headerSize || !bytes.Equal(archive[:len(magic)], magic) {
+		return nil, nil, fmt.Errorf("not a wallet archive")
+	}
+	version := binary.BigEndian.Uint16(archive[len(magic):])
+	if version != Version {
+		return nil, nil, fmt.Errorf("unsupported wallet archive version %d", version)
+	}
+	iter := binary.BigEndian.Uint32(archive[len(magic)+2:])
+	salt := archive[len(magic)+6 : headerSize]
+
+	aead, err := newAEAD(passphrase, salt, int(iter))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(archive) < headerSize+aead.NonceSize() {
+		return nil, nil, fmt.Errorf("not a wallet archive")
+	}
+	nonce := archive[headerSize : headerSize+aead.NonceSize()]
+	header := archive[:headerSize+aead.NonceSize()]
+
+	plain, err := aead.Open(nil, nonce, archive[len(header):], header)
+	if err != nil {
+		return nil, nil, ErrIntegrity
+	}
+
+	var p payload
+	if err := json.Unmarshal(plain, &p); err != nil || p.Manifest == nil {
+		return nil, nil, fmt.Errorf("invalid wallet archive contents")
+	}
+	if len(p.Records) != p.Manifest.Records || migrate.Checksum(p.Records) != p.Manifest.Checksum {
+		return nil, nil, ErrIntegrity
+	}
+	return p.Manifest, p.Records, nil
+}
+
+func newAEAD(passphrase string, salt []byte, iter int) (cipher.AEAD, error) {
+	if iter <= 0 || iter > maxIterations {
+		return nil, fmt.Errorf("invalid key derivation iterations %d", iter)
+	}
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, iter, keySize, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// lookup returns the info of the wallet with the given name
+func lookup(name string) (*wallet.Info, error) {
+	infos, err := wallet.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if info.Name == name {
+			return info, nil
+		}
+	}
+	return nil, fmt.Errorf("wallet [%s] doesn't exist", name)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/wallet"
+	"github.com/hyperledger/indy-sdk-go/wallet/inmem"
+	"github.com/hyperledger/indy-sdk-go/wallet/migrate"
+)
+
+const walletTypeName = "backup_inmem"
+
+func TestExportImport(t *testing.T) {
+	walletName := "backup_wallet1"
+
+	if err := wallet.RegisterType(walletTypeName, inmem.New()); err != nil {
+		t.Fatalf("Error received from RegisterType: %s", err)
+	}
+
+	// Delete any wallet that was created from a previous test
+	if exists, _ := wallet.Exists(walletName); exists {
+		wallet.Delete(walletName, "")
+	}
+	if err := wallet.Create("pool1", walletName, walletTypeName, "", ""); err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+	w, err := wallet.Open(walletName, "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	storage, err := w.Storage()
+	if err != nil {
+		t.Fatalf("Error received from Storage: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		storage.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("secret%d", i))
+	}
+
+	var archive bytes.Buffer
+	manifest, err := Export(w, &archive, "passphrase1")
+	if err != nil {
+		t.Fatalf("Error received from Export: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+	if manifest.Records != 10 || manifest.Wallet != walletName || manifest.PoolName != "pool1" ||
+		manifest.Type != walletTypeName || manifest.Version != Version {
+		t.Fatalf("Unexpected manifest: %+v", manifest)
+	}
+
+	// The exported wallet still exists
+	if _, err := Import(bytes.NewReader(archive.Bytes()), "passphrase1", "", ""); err == nil {
+		t.Fatalf("Expecting error importing into an existing wallet")
+	}
+
+	if err := wallet.Delete(walletName, ""); err != nil {
+		t.Fatalf("Error received from Delete: %s", err)
+	}
+	imported, err := Import(bytes.NewReader(archive.Bytes()), "passphrase1", "", "")
+	if err != nil {
+		t.Fatalf("Error received from Import: %s", err)
+	}
+	if imported.Checksum != manifest.Checksum {
+		t.Fatalf("Expecting checksum [%s] but got [%s]", manifest.Checksum, imported.Checksum)
+	}
+
+	// The restored wallet is a regular libindy wallet
+	w, err = wallet.Open(walletName, "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open of imported wallet: %s", err)
+	}
+	defer wallet.Delete(walletName, "")
+	defer w.Close()
+
+	storage, err = w.Storage()
+	if err != nil {
+		t.Fatalf("Error received from Storage: %s", err)
+	}
+	records, err := storage.List("")
+	if err != nil {
+		t.Fatalf("Error received from List: %s", err)
+	}
+	if len(records) != 10 {
+		t.Fatalf("Expecting 10 records but got %d", len(records))
+	}
+	if value, err := storage.Get("key3"); err != nil || value != "secret3" {
+		t.Fatalf("Expecting value [secret3] but got [%s]: %v", value, err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	records := []wallet.Record{{Key: "key1", Value: "secret1"}, {Key: "key2", Value: "secret2"}}
+	manifest := &Manifest{Version: Version, Wallet: "wallet1", Records: 2, Checksum: migrate.Checksum(records)}
+
+	var archive bytes.Buffer
+	if err := write(&archive, "passphrase1", &payload{Manifest: manifest, Records: records}); err != nil {
+		t.Fatalf("Error received from write: %s", err)
+	}
+	if bytes.Contains(archive.Bytes(), []byte("secret")) {
+		t.Fatalf("Expecting archive to be encrypted")
+	}
+
+	read, readRecords, err := Read(bytes.NewReader(archive.Bytes()), "passphrase1")
+	if err != nil {
+		t.Fatalf("Error received from Read: %s", err)
+	}
+	if read.Wallet != "wallet1" || migrate.Checksum(readRecords) != manifest.Checksum {
+		t.Fatalf("Unexpected archive contents: %+v", read)
+	}
+
+	if _, _, err := Read(bytes.NewReader(archive.Bytes()), "wrong"); err != ErrIntegrity {
+		t.Fatalf("Expecting error [%s] for wrong passphrase but got [%v]", ErrIntegrity, err)
+	}
+	tampered := append([]byte{}, archive.Bytes()...)
+	tampered[len(tampered)-1] ^= 1
+	if _, _, err := Read(bytes.NewReader(tampered), "passphrase1"); err != ErrIntegrity {
+		t.Fatalf("Expecting error [%s] for tampered archive but got [%v]", ErrIntegrity, err)
+	}
+	tampered = append([]byte{}, archive.Bytes()...)
+	tampered[len(magic)+1] = 2
+	if _, _, err := Read(bytes.NewReader(tampered), "passphrase1"); err == nil {
+		t.Fatalf("Expecting error for unsupported version")
+	}
+
+	// The manifest must match the records
+	manifest.Records = 3
+	archive.Reset()
+	write(&archive, "passphrase1", &payload{Manifest: manifest, Records: records})
+	if _, _, err := Read(bytes.NewReader(archive.Bytes()), "passphrase1"); err != ErrIntegrity {
+		t.Fatalf("Expecting error [%s] for wrong record count but got [%v]", ErrIntegrity, err)
+	}
+}
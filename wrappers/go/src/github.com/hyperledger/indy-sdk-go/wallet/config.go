@@ -0,0 +1,362 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFreshnessTime is the freshness time used when the runtime config doesn't specify one
+	DefaultFreshnessTime = 1000 * time.Second
+
+	// NoExpiry may be used as the freshness time so that records never expire
+	NoExpiry time.Duration = -1
+
+	redacted = "****"
+)
+
+// Config is the wallet configuration json passed to Create
+type Config struct {
+	// Dir is the directory in which the wallet is stored, for wallet types that store
+	// wallets in the file system. Defaults to a directory chosen by the wallet type.
+	Dir string
+
+	// Extensions contains additional keys supported by the wallet type. They
+	// are merged into the top level of the json.
+	Extensions map[string]interface{}
+}
+
+// RuntimeConfig is the runtime wallet configuration json passed to Open
+type RuntimeConfig struct {
+	// FreshnessTime is the time after which a record is considered expired. The json contains
+	// whole seconds. Defaults to DefaultFreshnessTime. Use NoExpiry for records that never expire.
+	FreshnessTime time.Duration
+
+	// Extensions contains additional keys supported by the wallet type. They
+	// are merged into the top level of the json.
+	Extensions map[string]interface{}
+}
+
+// Credentials is the wallet credentials json passed to Create, Open and Delete.
+// The key and any extensions are never revealed when the credentials are formatted,
+// for example when they are logged. Only the json contains the secrets.
+type Credentials struct {
+	// Key is the key (passphrase) of the wallet
+	Key string
+
+	// Rekey is the new key of the wallet. It's only used by Open.
+	Rekey string
+
+	// Extensions contains additional keys supported by the wallet type. They
+	// are merged into the top level of the json.
+	Extensions map[string]interface{}
+}
+
+// ParseConfig parses the given wallet configuration json. An empty string results in an empty config.
+func ParseConfig(config string) (*Config, error) {
+	c := &Config{}
+	if err := parseJSON(config, c); err != nil {
+		return nil, fmt.Errorf("invalid wallet config: %s", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate validates the config
+func (c *Config) Validate() error {
+	return validateExtensions(c.Extensions, "dir")
+}
+
+// JSON returns the config as json
+func (c *Config) JSON() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	return toJSON(c)
+}
+
+// MarshalJSON marshals the config
+func (c Config) MarshalJSON() ([]byte, error) {
+	m := merge(c.Extensions)
+	if c.Dir != "" {
+		m["dir"] = c.Dir
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON unmarshals the config
+func (c *Config) UnmarshalJSON(b []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	dir, err := stringField(m, "dir")
+	if err != nil {
+		return err
+	}
+	c.Dir = dir
+	c.Extensions = extensions(m, "dir")
+	return nil
+}
+
+// ParseRuntimeConfig parses the given runtime wallet configuration json. An empty string
+// results in the default runtime config.
+func ParseRuntimeConfig(runtimeConfig string) (*RuntimeConfig, error) {
+	c := &RuntimeConfig{}
+	if err := parseJSON(runtimeConfig, c); err != nil {
+		return nil, fmt.Errorf("invalid wallet runtime config: %s", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate validates the runtime config
+func (c *RuntimeConfig) Validate() error {
+	if c.FreshnessTime > 0 && c.FreshnessTime < time.Second {
+		return fmt.Errorf("freshness time must be at least one second")
+	}
+	if c.FreshnessTime < 0 && c.FreshnessTime != NoExpiry {
+		return fmt.Errorf("freshness time must not be negative")
+	}
+	return validateExtensions(c.Extensions, "freshness_time")
+}
+
+// Freshness returns the time after which a record is considered expired,
+// or zero if records never expire
+func (c *RuntimeConfig) Freshness() time.Duration {
+	switch c.FreshnessTime {
+	case 0:
+		return DefaultFreshnessTime
+	case NoExpiry:
+		return 0
+	default:
+		return c.FreshnessTime
+	}
+}
+
+// JSON returns the runtime config as json
+func (c *RuntimeConfig) JSON() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	return toJSON(c)
+}
+
+// MarshalJSON marshals the runtime config
+func (c RuntimeConfig) MarshalJSON() ([]byte, error) {
+	m := merge(c.Extensions)
+	switch {
+	case c.FreshnessTime == NoExpiry:
+		m["freshness_time"] = 0
+	case c.FreshnessTime > 0:
+		m["freshness_time"] = int64(c.FreshnessTime / time.Second)
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON unmarshals the runtime config
+func (c *RuntimeConfig) UnmarshalJSON(b []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	c.FreshnessTime = 0
+	if v, ok := m["freshness_time"]; ok {
+		seconds, ok := v.(float64)
+		if !ok || seconds != float64(int64(seconds)) {
+			return fmt.Errorf("freshness_time must be a whole number of seconds")
+		}
+		if seconds == 0 {
+			c.FreshnessTime = NoExpiry
+		} else {
+			c.FreshnessTime = time.Duration(seconds) * time.Second
+		}
+	}
+	c.Extensions = extensions(m, "freshness_time")
+	return nil
+}
+
+// ParseCredentials parses the given wallet credentials json. An empty string results in empty credentials.
+func ParseCredentials(credentials string) (*Credentials, error) {
+	c := &Credentials{}
+	if err := parseJSON(credentials, c); err != nil {
+		// Don't include the error since it may contain parts of the credentials
+		return nil, fmt.Errorf("invalid wallet credentials")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate validates the credentials
+func (c *Credentials) Validate() error {
+	if c.Rekey != "" && c.Key == "" {
+		return fmt.Errorf("key must be specified when changing the key")
+	}
+	return validateExtensions(c.Extensions, "key", "rekey")
+}
+
+// JSON returns the credentials as json. The json contains the secrets so it must not be logged.
+func (c *Credentials) JSON() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	return toJSON(c)
+}
+
+// MarshalJSON marshals the credentials
+func (c Credentials) MarshalJSON() ([]byte, error) {
+	m := merge(c.Extensions)
+	if c.Key != "" {
+		m["key"] = c.Key
+	}
+	if c.Rekey != "" {
+		m["rekey"] = c.Rekey
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON unmarshals the credentials
+func (c *Credentials) UnmarshalJSON(b []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	key, err := stringField(m, "key")
+	if err != nil {
+		return err
+	}
+	rekey, err := stringField(m, "rekey")
+	if err != nil {
+		return err
+	}
+	c.Key = key
+	c.Rekey = rekey
+	c.Extensions = extensions(m, "key", "rekey")
+	return nil
+}
+
+// String returns the credentials with all secrets redacted
+func (c Credentials) String() string {
+	s := "{Key:" + redactedString(c.Key)
+	if c.Rekey != "" {
+		s += " Rekey:" + redacted
+	}
+	for k := range c.Extensions {
+		s += " " + k + ":" + redacted
+	}
+	return s + "}"
+}
+
+// GoString returns the credentials with all secrets redacted
+func (c Credentials) GoString() string {
+	return "wallet.Credentials" + c.String()
+}
+
+// Format ensures that the secrets are redacted for every formatting verb
+func (c Credentials) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, c.GoString())
+		return
+	}
+	fmt.Fprint(f, c.String())
+}
+
+// redactedCredentials returns the given credentials json with the secrets redacted, for logging
+func redactedCredentials(credentials string) string {
+	if credentials == "" {
+		return ""
+	}
+	c, err := ParseCredentials(credentials)
+	if err != nil {
+		return redacted
+	}
+	return c.String()
+}
+
+func redactedString(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func parseJSON(s string, v interface{}) error {
+	if s == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(s), v)
+}
+
+func toJSON(v interface{}) (string, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func validateExtensions(ext map[string]interface{}, reserved ...string) error {
+	for _, key := range reserved {
+		if _, ok := ext[key]; ok {
+			return fmt.Errorf("extension [%s] conflicts with a predefined key", key)
+		}
+	}
+	return nil
+}
+
+func merge(ext map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(ext)+2)
+	for k, v := range ext {
+		m[k] = v
+	}
+	return m
+}
+
+func stringField(m map[string]interface{}, key string) (string, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return s, nil
+}
+
+// extensions returns the keys of m other than the given predefined keys
+func extensions(m map[string]interface{}, predefined ...string) map[string]interface{} {
+	var ext map[string]interface{}
+	for k, v := range m {
+		if contains(predefined, k) {
+			continue
+		}
+		if ext == nil {
+			ext = make(map[string]interface{})
+		}
+		ext[k] = v
+	}
+	return ext
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	config := &Config{
+		Dir:        "/tmp/wallets",
+		Extensions: map[string]interface{}{"cache_size": 10},
+	}
+	configJSON, err := config.JSON()
+	if err != nil {
+		t.Fatalf("Error received from JSON: %s", err)
+	}
+	if configJSON != `{"cache_size":10,"dir":"/tmp/wallets"}` {
+		t.Fatalf("Unexpected config json: %s", configJSON)
+	}
+
+	parsed, err := ParseConfig(configJSON)
+	if err != nil {
+		t.Fatalf("Error received from ParseConfig: %s", err)
+	}
+	if parsed.Dir != config.Dir || parsed.Extensions["cache_size"] != float64(10) {
+		t.Fatalf("Unexpected parsed config: %+v", parsed)
+	}
+
+	config.Extensions["dir"] = "/other"
+	if _, err := config.JSON(); err == nil {
+		t.Fatalf("Expecting error for extension that conflicts with a predefined key")
+	}
+	if _, err := ParseConfig(`{"dir": 1}`); err == nil {
+		t.Fatalf("Expecting error for invalid dir")
+	}
+}
+
+func TestRuntimeConfig(t *testing.T) {
+	tests := []struct {
+		json      string
+		freshness time.Duration
+	}{
+		{``, DefaultFreshnessTime},
+		{`{}`, DefaultFreshnessTime},
+		{`{"freshness_time": 5}`, 5 * time.Second},
+		{`{"freshness_time": 0}`, 0},
+	}
+	for _, test := range tests {
+		rc, err := ParseRuntimeConfig(test.json)
+		if err != nil {
+			t.Fatalf("Error received from ParseRuntimeConfig for [%s]: %s", test.json, err)
+		}
+		if rc.Freshness() != test.freshness {
+			t.Fatalf("Expecting freshness %s for [%s] but got %s", test.freshness, test.json, rc.Freshness())
+		}
+	}
+
+	for _, rc := range []*RuntimeConfig{{FreshnessTime: time.Millisecond}, {FreshnessTime: -time.Second}} {
+		if _, err := rc.JSON(); err == nil {
+			t.Fatalf("Expecting error for freshness time %s", rc.FreshnessTime)
+		}
+	}
+	if _, err := ParseRuntimeConfig(`{"freshness_time": 1.5}`); err == nil {
+		t.Fatalf("Expecting error for fractional freshness time")
+	}
+
+	rcJSON, err := (&RuntimeConfig{FreshnessTime: NoExpiry}).JSON()
+	if err != nil {
+		t.Fatalf("Error received from JSON: %s", err)
+	}
+	if rcJSON != `{"freshness_time":0}` {
+		t.Fatalf("Unexpected runtime config json: %s", rcJSON)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	creds := &Credentials{
+		Key:        "secret-key",
+		Rekey:      "secret-rekey",
+		Extensions: map[string]interface{}{"token": "secret-token"},
+	}
+
+	credsJSON, err := creds.JSON()
+	if err != nil {
+		t.Fatalf("Error received from JSON: %s", err)
+	}
+	parsed, err := ParseCredentials(credsJSON)
+	if err != nil {
+		t.Fatalf("Error received from ParseCredentials: %s", err)
+	}
+	if parsed.Key != creds.Key || parsed.Rekey != creds.Rekey || parsed.Extensions["token"] != "secret-token" {
+		t.Fatalf("Unexpected parsed credentials")
+	}
+
+	wrapper := struct {
+		Creds *Credentials
+		Value Credentials
+	}{creds, *creds}
+	for _, s := range []string{
+		creds.String(),
+		fmt.Sprint(creds),
+		fmt.Sprintf("%s %v %+v %#v %q %x", creds, creds, creds, creds, creds, creds),
+		fmt.Sprintf("%v %+v %#v", *creds, *creds, *creds),
+		fmt.Sprintf("%+v %v", wrapper, wrapper),
+		redactedCredentials(credsJSON),
+		redactedCredentials(`{"key": "secret-key", `),
+	} {
+		if strings.Contains(s, "secret") {
+			t.Fatalf("Credentials revealed: %s", s)
+		}
+	}
+
+	if _, err := (&Credentials{Rekey: "rekey"}).JSON(); err == nil {
+		t.Fatalf("Expecting error for rekey without key")
+	}
+	if _, err := ParseCredentials(`{"key": "secret-key"`); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Expecting error without secrets for invalid credentials but got [%v]", err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables read by the default environment credentials provider
+const DefaultEnvPrefix = "INDY_WALLET_KEY_"
+
+// CredentialsProvider provides the credentials of wallets so that
+// callers don't have to hold on to wallet keys
+type CredentialsProvider interface {
+	// Credentials returns the credentials of the wallet with the given name
+	Credentials(walletName string) (*Credentials, error)
+}
+
+// CredentialsProviderFunc is a function that implements CredentialsProvider
+type CredentialsProviderFunc func(walletName string) (*Credentials, error)
+
+// Credentials returns the credentials of the wallet with the given name
+func (f CredentialsProviderFunc) Credentials(walletName string) (*Credentials, error) {
+	return f(walletName)
+}
+
+// EnvCredentialsProvider reads the credentials of a wallet from an environment variable.
+// The name of the variable is the prefix followed by the wallet name in upper case with
+// all characters other than letters and digits replaced by '_'. For example, the key of
+// wallet 'my-wallet' is read from INDY_WALLET_KEY_MY_WALLET with the default prefix.
+//
+// The value is either the wallet key or a credentials json object.
+type EnvCredentialsProvider struct {
+	prefix string
+}
+
+// NewEnvCredentialsProvider returns a credentials provider that reads environment variables
+// with the given prefix. DefaultEnvPrefix is used if the prefix is empty.
+func NewEnvCredentialsProvider(prefix string) *EnvCredentialsProvider {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	return &EnvCredentialsProvider{prefix: prefix}
+}
+
+// Credentials returns the credentials of the wallet with the given name
+func (p *EnvCredentialsProvider) Credentials(walletName string) (*Credentials, error) {
+	name := p.VarName(walletName)
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("credentials of wallet [%s] not found: environment variable %s is not set", walletName, name)
+	}
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return ParseCredentials(value)
+	}
+	return &Credentials{Key: value}, nil
+}
+
+// VarName returns the name of the environment variable that contains the credentials of the given wallet
+func (p *EnvCredentialsProvider) VarName(walletName string) string {
+	return p.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, walletName)
+}
+
+// FileCredentialsProvider reads the credentials of wallets from a json file that maps
+// wallet names to credentials objects, for example:
+//
+//	{"wallet1": {"key": "..."}, "wallet2": {"key": "..."}}
+//
+// The file must only be accessible by its owner (e.g. mode 0600). It's read on each request
+// so that credentials may be changed without restarting the process.
+type FileCredentialsProvider struct {
+	path string
+}
+
+// NewFileCredentialsProvider returns a credentials provider that reads the given file
+func NewFileCredentialsProvider(path string) *FileCredentialsProvider {
+	return &FileCredentialsProvider{path: path}
+}
+
+// Credentials returns the credentials of the wallet with the given name
+func (p *FileCredentialsProvider) Credentials(walletName string) (*Credentials, error) {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file: %s", err)
+	}
+	// Windows doesn't support Unix permissions
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("credentials file [%s] must not be accessible by group or others (mode %s)", p.path, info.Mode().Perm())
+	}
+
+	bytes, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file: %s", err)
+	}
+	var creds map[string]*Credentials
+	if err := json.Unmarshal(bytes, &creds); err != nil {
+		// Don't include the error since it may contain parts of the credentials
+		return nil, fmt.Errorf("invalid credentials file [%s]", p.path)
+	}
+
+	c, ok := creds[walletName]
+	if !ok || c == nil {
+		return nil, fmt.Errorf("credentials of wallet [%s] not found in [%s]", walletName, p.path)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvCredentialsProvider(t *testing.T) {
+	provider := NewEnvCredentialsProvider("")
+	if name := provider.VarName("my-wallet.1"); name != "INDY_WALLET_KEY_MY_WALLET_1" {
+		t.Fatalf("Unexpected variable name: %s", name)
+	}
+
+	os.Setenv("INDY_WALLET_KEY_WALLET1", "key1")
+	os.Setenv("INDY_WALLET_KEY_WALLET2", `{"key": "key2", "rekey": "key3"}`)
+	defer os.Unsetenv("INDY_WALLET_KEY_WALLET1")
+	defer os.Unsetenv("INDY_WALLET_KEY_WALLET2")
+
+	creds, err := provider.Credentials("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Credentials: %s", err)
+	}
+	if creds.Key != "key1" {
+		t.Fatalf("Unexpected key for wallet1")
+	}
+	creds, err = provider.Credentials("wallet2")
+	if err != nil {
+		t.Fatalf("Error received from Credentials: %s", err)
+	}
+	if creds.Key != "key2" || creds.Rekey != "key3" {
+		t.Fatalf("Unexpected credentials for wallet2")
+	}
+	if _, err := provider.Credentials("wallet3"); err == nil {
+		t.Fatalf("Expecting error for missing environment variable")
+	}
+}
+
+func TestFileCredentialsProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "credentials.json")
+	if err := ioutil.WriteFile(path, []byte(`{"wallet1": {"key": "secret-key1"}}`), 0644); err != nil {
+		t.Fatalf("Error writing credentials file: %s", err)
+	}
+
+	provider := NewFileCredentialsProvider(path)
+	if _, err := provider.Credentials("wallet1"); err == nil || !strings.Contains(err.Error(), "must not be accessible") {
+		t.Fatalf("Expecting error for file that is readable by others but got [%v]", err)
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatalf("Error changing mode of credentials file: %s", err)
+	}
+	creds, err := provider.Credentials("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Credentials: %s", err)
+	}
+	if creds.Key != "secret-key1" {
+		t.Fatalf("Unexpected key for wallet1")
+	}
+	if _, err := provider.Credentials("wallet2"); err == nil {
+		t.Fatalf("Expecting error for unknown wallet")
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`{"wallet1": {"key": "secret-key1"`), 0600); err != nil {
+		t.Fatalf("Error writing credentials file: %s", err)
+	}
+	if _, err := provider.Credentials("wallet1"); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("Expecting error without secrets for invalid file but got [%v]", err)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inmem
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+const (
+	// TypeName is the name under which the in-memory wallet type is registered
+	TypeName = "inmem"
+)
+
+// Type is a wallet type whose records live only in process memory.
+//
+// libindy keeps the metadata of created wallets on disk so a wallet created in a previous
+// process may still 'exist' after its records are gone. Such a wallet is opened as an empty wallet.
+type Type struct {
+	mutex   sync.RWMutex
+	wallets map[string]map[string]*record
+}
+
+type record struct {
+	value   string
+	created time.Time
+}
+
+// Snapshot contains the records of an in-memory wallet at a point in time
+type Snapshot struct {
+	Records []SnapshotRecord `json:"records"`
+}
+
+// SnapshotRecord is a record contained in a Snapshot
+type SnapshotRecord struct {
+	Key     string    `json:"key"`
+	Value   string    `json:"value"`
+	Created time.Time `json:"created"`
+}
+
+// New returns a new in-memory wallet type. The type must be registered
+// (see wallet.RegisterType) before wallets of the type may be created.
+func New() *Type {
+	return &Type{
+		wallets: make(map[string]map[string]*record),
+	}
+}
+
+var (
+	defaultType  = New()
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// Register registers the default in-memory wallet type under TypeName.
+// Subsequent calls return the result of the first registration.
+func Register() error {
+	registerOnce.Do(func() {
+		registerErr = wallet.RegisterType(TypeName, defaultType)
+	})
+	return registerErr
+}
+
+// Default returns the in-memory wallet type that is registered by Register
+func Default() *Type {
+	return defaultType
+}
+
+// Create creates a new, empty wallet
+func (t *Type) Create(name, config, credentials string) error {
+	logger.Debugf("Creating in-memory wallet [%s]", name)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.wallets[name]; ok {
+		// libindy checks whether the wallet exists before calling create
+		return indyerror.New(indyerror.CommonInvalidState)
+	}
+	t.wallets[name] = make(map[string]*record)
+	return nil
+}
+
+// Open opens the wallet with the given name.
+//
+// runtimeConfig Runtime configuration json (see wallet.RuntimeConfig).
+func (t *Type) Open(name, config, runtimeConfig, credentials string) (wallet.Storage, error) {
+	logger.Debugf("Opening in-memory wallet [%s]", name)
+
+	rc, err := wallet.ParseRuntimeConfig(runtimeConfig)
+	if err != nil {
+		logger.Warnf("Invalid runtime config for in-memory wallet [%s]: %s", name, err)
+		return nil, indyerror.New(indyerror.CommonInvalidStructure)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.wallets[name]; !ok {
+		logger.Debugf("Records of in-memory wallet [%s] don't exist in this process. Opening empty wallet.", name)
+		t.wallets[name] = make(map[string]*record)
+	}
+
+	return &storage{
+		walletType:    t,
+		name:          name,
+		freshnessTime: rc.Freshness(),
+	}, nil
+}
+
+// Delete deletes the wallet and all of its records
+func (t *Type) Delete(name, config, credentials string) error {
+	logger.Debugf("Deleting in-memory wallet [%s]", name)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.wallets, name)
+	return nil
+}
+
+// Snapshot returns a copy of the records of the given wallet
+func (t *Type) Snapshot(name string) (*Snapshot, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	records, ok := t.wallets[name]
+	if !ok {
+		return nil, fmt.Errorf("in-memory wallet [%s] not found", name)
+	}
+
+	snapshot := &Snapshot{Records: []SnapshotRecord{}}
+	for key, r := range records {
+		snapshot.Records = append(snapshot.Records, SnapshotRecord{
+			Key:     key,
+			Value:   r.value,
+			Created: r.created,
+		})
+	}
+	return snapshot, nil
+}
+
+// Restore replaces the records of the given wallet with the records of the snapshot.
+// The wallet doesn't have to exist in this process but it must have been created
+// with libindy before it may be opened.
+func (t *Type) Restore(name string, snapshot *Snapshot) error {
+	if snapshot == nil {
+		return fmt.Errorf("snapshot must be specified")
+	}
+
+	records := make(map[string]*record)
+	for _, r := range snapshot.Records {
+		records[r.Key] = &record{
+			value:   r.Value,
+			created: r.Created,
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.wallets[name] = records
+	return nil
+}
+
+type storage struct {
+	walletType    *Type
+	name          string
+	freshnessTime time.Duration
+}
+
+func (s *storage) Set(key, value string) error {
+	s.walletType.mutex.Lock()
+	defer s.walletType.mutex.Unlock()
+
+	records, err := s.records()
+	if err != nil {
+		return err
+	}
+	records[key] = &record{
+		value:   value,
+		created: time.Now(),
+	}
+	return nil
+}
+
+func (s *storage) Get(key string) (string, error) {
+	s.walletType.mutex.RLock()
+	defer s.walletType.mutex.RUnlock()
+
+	r, err := s.get(key)
+	if err != nil {
+		return "", err
+	}
+	return r.value, nil
+}
+
+func (s *storage) GetNotExpired(key string) (string, error) {
+	s.walletType.mutex.RLock()
+	defer s.walletType.mutex.RUnlock()
+
+	r, err := s.get(key)
+	if err != nil {
+		return "", err
+	}
+	if s.freshnessTime > 0 && time.Since(r.created) > s.freshnessTime {
+		return "", indyerror.New(indyerror.WalletNotFoundError)
+	}
+	return r.value, nil
+}
+
+func (s *storage) List(keyPrefix string) ([]wallet.Record, error) {
+	s.walletType.mutex.RLock()
+	defer s.walletType.mutex.RUnlock()
+
+	records, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []wallet.Record
+	for key, r := range records {
+		if strings.HasPrefix(key, keyPrefix) {
+			result = append(result, wallet.Record{Key: key, Value: r.value})
+		}
+	}
+	return result, nil
+}
+
+func (s *storage) Close() error {
+	logger.Debugf("Closing in-memory wallet [%s]", s.name)
+	return nil
+}
+
+// records returns the records of the wallet. The caller must hold the lock.
+func (s *storage) records() (map[string]*record, error) {
+	records, ok := s.walletType.wallets[s.name]
+	if !ok {
+		// The wallet was deleted while open
+		return nil, indyerror.New(indyerror.CommonInvalidState)
+	}
+	return records, nil
+}
+
+// get returns the record for the given key. The caller must hold the lock.
+func (s *storage) get(key string) (*record, error) {
+	records, err := s.records()
+	if err != nil {
+		return nil, err
+	}
+	r, ok := records[key]
+	if !ok {
+		return nil, indyerror.New(indyerror.WalletNotFoundError)
+	}
+	return r, nil
+}
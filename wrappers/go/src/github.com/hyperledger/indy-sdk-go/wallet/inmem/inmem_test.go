@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inmem
+
+import (
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+func TestInmemStorage(t *testing.T) {
+	walletType := New()
+
+	err := walletType.Create("wallet1", "", "")
+	if err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+
+	storage, err := walletType.Open("wallet1", "", `{"freshness_time": 1000}`, "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+
+	if err := storage.Set("key1::a", "value1"); err != nil {
+		t.Fatalf("Error received from Set: %s", err)
+	}
+	if err := storage.Set("key2::a", "value2"); err != nil {
+		t.Fatalf("Error received from Set: %s", err)
+	}
+
+	value, err := storage.GetNotExpired("key1::a")
+	if err != nil {
+		t.Fatalf("Error received from GetNotExpired: %s", err)
+	}
+	if value != "value1" {
+		t.Fatalf("Expecting [value1] but got [%s]", value)
+	}
+
+	_, err = storage.Get("key3")
+	if indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletNotFoundError), err)
+	}
+
+	records, err := storage.List("key1::")
+	if err != nil {
+		t.Fatalf("Error received from List: %s", err)
+	}
+	if len(records) != 1 || records[0].Key != "key1::a" {
+		t.Fatalf("Expecting one record with key [key1::a] but got %v", records)
+	}
+
+	snapshot, err := walletType.Snapshot("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Snapshot: %s", err)
+	}
+
+	if err := walletType.Delete("wallet1", "", ""); err != nil {
+		t.Fatalf("Error received from Delete: %s", err)
+	}
+	if _, err := storage.Get("key1::a"); err == nil {
+		t.Fatalf("Expecting error getting a record of a deleted wallet")
+	}
+
+	if err := walletType.Restore("wallet1", snapshot); err != nil {
+		t.Fatalf("Error received from Restore: %s", err)
+	}
+	value, err = storage.Get("key2::a")
+	if err != nil {
+		t.Fatalf("Error received from Get after Restore: %s", err)
+	}
+	if value != "value2" {
+		t.Fatalf("Expecting [value2] but got [%s]", value)
+	}
+}
+
+func TestInmemWallet(t *testing.T) {
+	walletName := "inmem_wallet1"
+
+	err := Register()
+	if err != nil {
+		t.Fatalf("Error received from Register: %s", err)
+	}
+
+	err = wallet.Create("pool1", walletName, TypeName, "", "")
+	if err != nil && indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+	defer wallet.Delete(walletName, "")
+
+	w, err := wallet.Open(walletName, "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pbkdf2
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from RFC 6070
+func TestKey(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iter, keyLen   int
+		expected       string
+	}{
+		{"password", "salt", 1, 20, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 4096, 20, "4b007901b765489abead49d926f721d065a429c1"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 25, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+	}
+	for _, test := range tests {
+		key := hex.EncodeToString(Key([]byte(test.password), []byte(test.salt), test.iter, test.keyLen, sha1.New))
+		if key != test.expected {
+			t.Fatalf("Expecting key [%s] but got [%s]", test.expected, key)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+// dataKeySize is the size of the generated wallet keys in bytes
+const dataKeySize = 32
+
+// KMS is a key management service that wraps and unwraps data keys with master
+// keys that never leave the service
+type KMS interface {
+	// Wrap encrypts the data key with the given master key
+	Wrap(masterKeyID string, dataKey []byte) ([]byte, error)
+
+	// Unwrap decrypts a data key that was wrapped with the given master key
+	Unwrap(masterKeyID string, wrapped []byte) ([]byte, error)
+}
+
+// Local is an in-process stand-in for a KMS, for development and tests. Master keys
+// are AES-256-GCM keys that only live in memory; data keys wrapped by them can't
+// be unwrapped after the process exits.
+type Local struct {
+	mutex sync.RWMutex
+	keys  map[string]cipher.AEAD
+}
+
+// NewLocal returns a new in-process KMS without any master keys
+func NewLocal() *Local {
+	return &Local{keys: make(map[string]cipher.AEAD)}
+}
+
+// CreateMasterKey creates a random master key with the given ID
+func (k *Local) CreateMasterKey(masterKeyID string) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if _, ok := k.keys[masterKeyID]; ok {
+		return fmt.Errorf("master key [%s] already exists", masterKeyID)
+	}
+	k.keys[masterKeyID] = aead
+	return nil
+}
+
+// Wrap encrypts the data key with the given master key
+func (k *Local) Wrap(masterKeyID string, dataKey []byte) ([]byte, error) {
+	aead, err := k.masterKey(masterKeyID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// The master key ID is authenticated so that a data key can only be unwrapped with the same ID
+	return aead.Seal(nonce, nonce, dataKey, []byte(masterKeyID)), nil
+}
+
+// Unwrap decrypts a data key that was wrapped with the given master key
+func (k *Local) Unwrap(masterKeyID string, wrapped []byte) ([]byte, error) {
+	aead, err := k.masterKey(masterKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("invalid wrapped key")
+	}
+	dataKey, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(masterKeyID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap key with master key [%s]", masterKeyID)
+	}
+	return dataKey, nil
+}
+
+func (k *Local) masterKey(masterKeyID string) (cipher.AEAD, error) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	aead, ok := k.keys[masterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key [%s] not found", masterKeyID)
+	}
+	return aead, nil
+}
+
+// CredentialsProvider provides wallet keys that are stored wrapped by a KMS master key.
+// Only the wrapped keys need to be persisted by the application (see WrappedKeys); the
+// plaintext keys are unwrapped on demand.
+type CredentialsProvider struct {
+	kms         KMS
+	masterKeyID string
+	mutex       sync.RWMutex
+	wrapped     map[string][]byte
+}
+
+// NewCredentialsProvider returns a credentials provider that unwraps the given wrapped wallet keys
+// (by wallet name) with the given master key. wrappedKeys may be nil.
+func NewCredentialsProvider(kms KMS, masterKeyID string, wrappedKeys map[string][]byte) *CredentialsProvider {
+	wrapped := make(map[string][]byte)
+	for name, key := range wrappedKeys {
+		wrapped[name] = key
+	}
+	return &CredentialsProvider{
+		kms:         kms,
+		masterKeyID: masterKeyID,
+		wrapped:     wrapped,
+	}
+}
+
+// GenerateKey generates a random key for the given wallet and returns it wrapped by the master key.
+// The wrapped key should be persisted so that it can be passed to NewCredentialsProvider later.
+func (p *CredentialsProvider) GenerateKey(walletName string) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	wrapped, err := p.kms.Wrap(p.masterKeyID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("error wrapping key of wallet [%s]: %s", walletName, err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.wrapped[walletName]; ok {
+		return nil, fmt.Errorf("key of wallet [%s] already exists", walletName)
+	}
+	p.wrapped[walletName] = wrapped
+	return wrapped, nil
+}
+
+// WrappedKeys returns the wrapped keys by wallet name
+func (p *CredentialsProvider) WrappedKeys() map[string][]byte {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	keys := make(map[string][]byte, len(p.wrapped))
+	for name, key := range p.wrapped {
+		keys[name] = key
+	}
+	return keys
+}
+
+// Credentials returns the credentials of the wallet with the given name
+func (p *CredentialsProvider) Credentials(walletName string) (*wallet.Credentials, error) {
+	p.mutex.RLock()
+	wrapped, ok := p.wrapped[walletName]
+	p.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("key of wallet [%s] not found", walletName)
+	}
+	dataKey, err := p.kms.Unwrap(p.masterKeyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.Credentials{Key: base64.StdEncoding.EncodeToString(dataKey)}, nil
+}
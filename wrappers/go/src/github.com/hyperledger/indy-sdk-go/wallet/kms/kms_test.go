@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCredentialsProvider(t *testing.T) {
+	kms := NewLocal()
+	if err := kms.CreateMasterKey("master1"); err != nil {
+		t.Fatalf("Error received from CreateMasterKey: %s", err)
+	}
+	if err := kms.CreateMasterKey("master2"); err != nil {
+		t.Fatalf("Error received from CreateMasterKey: %s", err)
+	}
+
+	provider := NewCredentialsProvider(kms, "master1", nil)
+	wrapped, err := provider.GenerateKey("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from GenerateKey: %s", err)
+	}
+	if _, err := provider.GenerateKey("wallet1"); err == nil {
+		t.Fatalf("Expecting error generating a second key for the same wallet")
+	}
+
+	creds, err := provider.Credentials("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Credentials: %s", err)
+	}
+	if creds.Key == "" || bytes.Contains(wrapped, []byte(creds.Key)) {
+		t.Fatalf("Expecting the key to be wrapped")
+	}
+
+	// The persisted wrapped keys produce the same credentials
+	creds2, err := NewCredentialsProvider(kms, "master1", provider.WrappedKeys()).Credentials("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Credentials: %s", err)
+	}
+	if creds2.Key != creds.Key {
+		t.Fatalf("Expecting the same key from the persisted wrapped key")
+	}
+
+	if _, err := NewCredentialsProvider(kms, "master2", provider.WrappedKeys()).Credentials("wallet1"); err == nil {
+		t.Fatalf("Expecting error unwrapping with a different master key")
+	}
+	if _, err := provider.Credentials("wallet2"); err == nil {
+		t.Fatalf("Expecting error for unknown wallet")
+	}
+}
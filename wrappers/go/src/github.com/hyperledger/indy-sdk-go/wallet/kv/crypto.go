@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/hyperledger/indy-sdk-go/wallet/internal/pbkdf2"
+)
+
+const (
+	saltSize   = 16
+	keySize    = 32
+	iterations = 100000
+)
+
+// cipherKey encrypts and decrypts the records of a database
+type cipherKey struct {
+	aead cipher.AEAD
+}
+
+// newCipherKey derives an AES-256-GCM key from the given passphrase and salt
+func newCipherKey(passphrase string, salt []byte, iter int) (*cipherKey, error) {
+	key := pbkdf2.Key([]byte(passphrase), salt, iter, keySize, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &cipherKey{aead: aead}, nil
+}
+
+// seal encrypts the given plaintext. The nonce is prepended to the ciphertext.
+func (k *cipherKey) seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts and authenticates a value produced by seal
+func (k *cipherKey) open(sealed []byte) ([]byte, error) {
+	nonceSize := k.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf("sealed value is too short")
+	}
+	return k.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
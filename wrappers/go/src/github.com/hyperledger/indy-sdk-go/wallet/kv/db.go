@@ -0,0 +1,469 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+// The database is a single file consisting of a header followed by an append-only
+// log of encrypted records:
+//
+//   header:  magic[8] | iterations uint32 | salt[16] | len uint32 | sealed(magic)
+//   record:  len uint32 | sealed(created int64 | keyLen uint32 | key | value)
+//
+// All integers are big-endian. The most recent record for a key wins. The log is
+// compacted when it contains more superseded records than live ones.
+
+var magic = []byte("INDYKV\x00\x01")
+
+const (
+	// maxRecordSize protects against allocating huge buffers for corrupted lengths
+	maxRecordSize = 64 * 1024 * 1024
+
+	// minCompactGarbage is the minimum number of superseded records before compacting
+	minCompactGarbage = 1000
+)
+
+var errAccessFailed = fmt.Errorf("invalid wallet key")
+
+type entry struct {
+	value   string
+	created time.Time
+}
+
+type db struct {
+	mutex   sync.RWMutex
+	path    string
+	file    *os.File
+	lock    *os.File
+	key     *cipherKey
+	header  []byte
+	entries map[string]*entry
+	garbage int
+}
+
+// createDB creates a new, empty database file encrypted with the given passphrase
+func createDB(path, passphrase string) error {
+	salt, err := newSalt()
+	if err != nil {
+		return err
+	}
+	key, err := newCipherKey(passphrase, salt, iterations)
+	if err != nil {
+		return err
+	}
+	header, err := newHeader(key, salt, iterations)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(header); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return f.Sync()
+}
+
+// openDB opens the database file and loads its records. errAccessFailed is
+// returned if the passphrase doesn't match the one used to create the database.
+func openDB(path, passphrase string) (*db, error) {
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := loadDB(path, passphrase)
+	if err != nil {
+		unlockFile(lock)
+		return nil, err
+	}
+	d.lock = lock
+
+	if d.garbage >= minCompactGarbage && d.garbage > len(d.entries) {
+		if err := d.compact(); err != nil {
+			logger.Warnf("Error compacting wallet database [%s]: %s", path, err)
+		}
+	}
+	return d, nil
+}
+
+func loadDB(path, passphrase string) (*db, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &db{
+		path:    path,
+		file:    f,
+		entries: make(map[string]*entry),
+	}
+	if err := d.load(passphrase); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *db) load(passphrase string) error {
+	r := bufio.NewReader(d.file)
+
+	header, key, err := readHeader(r, passphrase)
+	if err != nil {
+		return err
+	}
+	d.header = header
+	d.key = key
+
+	offset := int64(len(header))
+	for {
+		sealed, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			// A record was only partially written, probably due to a crash
+			logger.Warnf("Truncating partially written record at offset %d of wallet database [%s]", offset, d.path)
+			if err := d.file.Truncate(offset); err != nil {
+				return err
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		plain, err := d.key.open(sealed)
+		if err != nil {
+			return fmt.Errorf("wallet database [%s] is corrupted at offset %d: %s", d.path, offset, err)
+		}
+		k, e, err := decodeEntry(plain)
+		if err != nil {
+			return fmt.Errorf("wallet database [%s] is corrupted at offset %d: %s", d.path, offset, err)
+		}
+		if _, ok := d.entries[k]; ok {
+			d.garbage++
+		}
+		d.entries[k] = e
+
+		offset += int64(4 + len(sealed))
+	}
+
+	_, err = d.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (d *db) get(key string) (*entry, bool) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	e, ok := d.entries[key]
+	return e, ok
+}
+
+func (d *db) put(key, value string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil {
+		return fmt.Errorf("wallet database [%s] is closed", d.path)
+	}
+
+	e := &entry{value: value, created: time.Now()}
+	sealed, err := d.key.seal(encodeEntry(key, e))
+	if err != nil {
+		return err
+	}
+	if err := d.append(frame(sealed)); err != nil {
+		return err
+	}
+
+	if _, ok := d.entries[key]; ok {
+		d.garbage++
+	}
+	d.entries[key] = e
+
+	if d.garbage >= minCompactGarbage && d.garbage > len(d.entries) {
+		if err := d.compact(); err != nil {
+			logger.Warnf("Error compacting wallet database [%s]: %s", d.path, err)
+		}
+	}
+	return nil
+}
+
+// append writes the framed record at the end of the log. If the write fails the log is
+// truncated to its previous end so that a partially written record isn't followed by later
+// records, which would make the log unreadable. The caller must hold the lock.
+func (d *db) append(framed []byte) error {
+	offset, err := d.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.file.Write(framed)
+	if err == nil {
+		err = d.file.Sync()
+	}
+	if err != nil {
+		if truncErr := d.truncate(offset); truncErr != nil {
+			// Refuse further writes rather than appending after a partial record
+			logger.Errorf("Error truncating wallet database [%s] after failed write: %s", d.path, truncErr)
+			d.file.Close()
+			d.file = nil
+		}
+		return err
+	}
+	return nil
+}
+
+// truncate discards everything after the given offset and positions the file at the offset.
+// The caller must hold the lock.
+func (d *db) truncate(offset int64) error {
+	if err := d.file.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := d.file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	return d.file.Sync()
+}
+
+func (d *db) list(keyPrefix string) []wallet.Record {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	var records []wallet.Record
+	for k, e := range d.entries {
+		if strings.HasPrefix(k, keyPrefix) {
+			records = append(records, wallet.Record{Key: k, Value: e.value})
+		}
+	}
+	return records
+}
+
+// compact rewrites the database with only the live records. The caller must hold the lock.
+func (d *db) compact() error {
+	logger.Debugf("Compacting wallet database [%s] - %d live records, %d superseded records", d.path, len(d.entries), d.garbage)
+
+	f, err := d.rewrite(d.header, d.key)
+	if err != nil {
+		return err
+	}
+
+	d.file.Close()
+	d.file = f
+	d.garbage = 0
+	return nil
+}
+
+// rewrite atomically replaces the database file with a file containing the given header and
+// the live records encrypted with the given key. The new file is returned, positioned at the end.
+// The caller must hold the lock.
+func (d *db) rewrite(header []byte, key *cipherKey) (*os.File, error) {
+	tmpPath := d.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeEntries(f, header, key, d.entries); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := syncDir(filepath.Dir(d.path)); err != nil {
+		logger.Warnf("Error syncing directory of wallet database [%s]: %s", d.path, err)
+	}
+	return f, nil
+}
+
+// rekey atomically re-encrypts the database with a key derived from the given passphrase.
+// If the process crashes during the rekey the database is encrypted either with the old or
+// with the new key.
+func (d *db) rekey(passphrase string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.file == nil {
+		return fmt.Errorf("wallet database [%s] is closed", d.path)
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		return err
+	}
+	key, err := newCipherKey(passphrase, salt, iterations)
+	if err != nil {
+		return err
+	}
+	header, err := newHeader(key, salt, iterations)
+	if err != nil {
+		return err
+	}
+
+	f, err := d.rewrite(header, key)
+	if err != nil {
+		return err
+	}
+
+	d.file.Close()
+	d.file = f
+	d.header = header
+	d.key = key
+	d.garbage = 0
+	return nil
+}
+
+func (d *db) close() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var err error
+	if d.file != nil {
+		err = d.file.Close()
+		d.file = nil
+	}
+	if d.lock != nil {
+		unlockFile(d.lock)
+		d.lock = nil
+	}
+	return err
+}
+
+func writeEntries(f *os.File, header []byte, key *cipherKey, entries map[string]*entry) error {
+	w := bufio.NewWriter(f)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	for k, e := range entries {
+		sealed, err := key.seal(encodeEntry(k, e))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(frame(sealed)); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func newHeader(key *cipherKey, salt []byte, iter int) ([]byte, error) {
+	check, err := key.seal(magic)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(magic)
+	binary.Write(&buf, binary.BigEndian, uint32(iter))
+	buf.Write(salt)
+	buf.Write(frame(check))
+	return buf.Bytes(), nil
+}
+
+func readHeader(r io.Reader, passphrase string) ([]byte, *cipherKey, error) {
+	fixed := make([]byte, len(magic)+4+saltSize)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, nil, fmt.Errorf("invalid wallet database header: %s", err)
+	}
+	if !bytes.Equal(fixed[:len(magic)], magic) {
+		return nil, nil, fmt.Errorf("not a wallet database")
+	}
+	iter := binary.BigEndian.Uint32(fixed[len(magic):])
+	salt := fixed[len(magic)+4:]
+
+	check, err := readRecord(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid wallet database header: %s", err)
+	}
+
+	key, err := newCipherKey(passphrase, salt, int(iter))
+	if err != nil {
+		return nil, nil, err
+	}
+	if plain, err := key.open(check); err != nil || !bytes.Equal(plain, magic) {
+		return nil, nil, errAccessFailed
+	}
+
+	return append(fixed, frame(check)...), key, nil
+}
+
+func readRecord(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxRecordSize {
+		return nil, fmt.Errorf("invalid record size %d", size)
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(r, record); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func frame(b []byte) []byte {
+	framed := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(framed, uint32(len(b)))
+	copy(framed[4:], b)
+	return framed
+}
+
+func encodeEntry(key string, e *entry) []byte {
+	b := make([]byte, 12+len(key)+len(e.value))
+	binary.BigEndian.PutUint64(b, uint64(e.created.UnixNano()))
+	binary.BigEndian.PutUint32(b[8:], uint32(len(key)))
+	copy(b[12:], key)
+	copy(b[12+len(key):], e.value)
+	return b
+}
+
+func decodeEntry(b []byte) (string, *entry, error) {
+	if len(b) < 12 {
+		return "", nil, fmt.Errorf("record is too short")
+	}
+	created := int64(binary.BigEndian.Uint64(b))
+	keyLen := int(binary.BigEndian.Uint32(b[8:]))
+	if keyLen > len(b)-12 {
+		return "", nil, fmt.Errorf("invalid key length %d", keyLen)
+	}
+	return string(b[12 : 12+keyLen]), &entry{
+		value:   string(b[12+keyLen:]),
+		created: time.Unix(0, created),
+	}, nil
+}
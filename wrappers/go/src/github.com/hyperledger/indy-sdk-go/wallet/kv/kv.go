@@ -0,0 +1,361 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+const (
+	// TypeName is the name under which the key/value wallet type is registered
+	TypeName = "kv"
+
+	fileExt = ".kvdb"
+)
+
+// Type is a wallet type that stores the records of each wallet in a single encrypted
+// file. Records are encrypted at rest with AES-256-GCM using a key derived from the
+// key in the wallet credentials (see wallet.Credentials), which is required.
+//
+// The file is stored in the directory given by the wallet config (see wallet.Config).
+// The directory defaults to $HOME/.indy_client/kv_wallet.
+//
+// A wallet may be opened more than once within the process; all handles share the
+// same database. The database is locked so that it can't be opened by other processes.
+type Type struct {
+	mutex sync.Mutex
+	dbs   map[string]*sharedDB
+}
+
+type sharedDB struct {
+	*db
+	refs int
+}
+
+// New returns a new key/value wallet type. The type must be registered
+// (see wallet.RegisterType) before wallets of the type may be created.
+func New() *Type {
+	return &Type{
+		dbs: make(map[string]*sharedDB),
+	}
+}
+
+var (
+	defaultType  = New()
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// Register registers the default key/value wallet type under TypeName.
+// Subsequent calls return the result of the first registration.
+func Register() error {
+	registerOnce.Do(func() {
+		registerErr = wallet.RegisterType(TypeName, defaultType)
+	})
+	return registerErr
+}
+
+// Create creates the database file of the wallet
+func (t *Type) Create(name, config, credentials string) error {
+	logger.Debugf("Creating key/value wallet [%s]", name)
+
+	path, err := dbPath(name, config)
+	if err != nil {
+		return err
+	}
+	creds, err := parseCredentials(credentials)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		logger.Errorf("Error creating directory for key/value wallet [%s]: %s", name, err)
+		return indyerror.New(indyerror.CommonIOError)
+	}
+	if err := createDB(path, creds.Key); err != nil {
+		if os.IsExist(err) {
+			return indyerror.New(indyerror.WalletAlreadyExistsError)
+		}
+		logger.Errorf("Error creating key/value wallet [%s]: %s", name, err)
+		return indyerror.New(indyerror.CommonIOError)
+	}
+	return nil
+}
+
+// Open opens the database of the wallet. If the credentials contain a 'rekey' the
+// database is re-encrypted with the new key (see Rekey) before it's opened.
+//
+// runtimeConfig Runtime configuration json (see wallet.RuntimeConfig).
+func (t *Type) Open(name, config, runtimeConfig, credentials string) (wallet.Storage, error) {
+	logger.Debugf("Opening key/value wallet [%s]", name)
+
+	path, err := dbPath(name, config)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := parseCredentials(credentials)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := wallet.ParseRuntimeConfig(runtimeConfig)
+	if err != nil {
+		logger.Warnf("Invalid runtime config for key/value wallet [%s]: %s", name, err)
+		return nil, indyerror.New(indyerror.CommonInvalidStructure)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	shared, ok := t.dbs[path]
+	if ok {
+		if creds.Rekey != "" {
+			logger.Errorf("Key/value wallet [%s] can't be rekeyed while it's open", name)
+			return nil, indyerror.New(indyerror.CommonInvalidState)
+		}
+		// The database is already open so only check the key
+		if err := verifyKey(path, creds.Key); err != nil {
+			return nil, dbError(name, err)
+		}
+	} else {
+		var d *db
+		if creds.Rekey != "" {
+			d, err = rekeyDB(path, creds.Key, creds.Rekey)
+		} else {
+			d, err = openDB(path, creds.Key)
+		}
+		if err != nil {
+			return nil, dbError(name, err)
+		}
+		shared = &sharedDB{db: d}
+		t.dbs[path] = shared
+	}
+	shared.refs++
+
+	return &storage{
+		walletType:    t,
+		name:          name,
+		path:          path,
+		db:            shared.db,
+		freshnessTime: rc.Freshness(),
+	}, nil
+}
+
+// Rekey atomically re-encrypts all records of the wallet with a key derived from the key in
+// newCredentials. The wallet must not be open. If the process crashes during the rekey the
+// wallet remains encrypted either with the old or with the new key; calling Rekey again with
+// the same credentials completes the rekey.
+func (t *Type) Rekey(name, config, credentials, newCredentials string) error {
+	logger.Debugf("Rekeying key/value wallet [%s]", name)
+
+	path, err := dbPath(name, config)
+	if err != nil {
+		return err
+	}
+	creds, err := parseCredentials(credentials)
+	if err != nil {
+		return err
+	}
+	newCreds, err := parseCredentials(newCredentials)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.dbs[path]; ok {
+		logger.Errorf("Key/value wallet [%s] can't be rekeyed while it's open", name)
+		return indyerror.New(indyerror.CommonInvalidState)
+	}
+
+	d, err := rekeyDB(path, creds.Key, newCreds.Key)
+	if err != nil {
+		return dbError(name, err)
+	}
+	return d.close()
+}
+
+// Delete deletes the database file of the wallet
+func (t *Type) Delete(name, config, credentials string) error {
+	logger.Debugf("Deleting key/value wallet [%s]", name)
+
+	path, err := dbPath(name, config)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.dbs[path]; ok {
+		logger.Errorf("Key/value wallet [%s] can't be deleted while it's open", name)
+		return indyerror.New(indyerror.CommonInvalidState)
+	}
+
+	// Take the lock of the database so that it isn't deleted while another process has it open
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		logger.Errorf("Key/value wallet [%s] can't be deleted: %s", name, err)
+		return indyerror.New(indyerror.CommonInvalidState)
+	}
+	defer unlockFile(lock)
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logger.Errorf("Error deleting key/value wallet [%s]: %s", name, err)
+		return indyerror.New(indyerror.CommonIOError)
+	}
+	os.Remove(path + ".lock")
+	return nil
+}
+
+func (t *Type) release(path string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	shared, ok := t.dbs[path]
+	if !ok {
+		return nil
+	}
+	shared.refs--
+	if shared.refs > 0 {
+		return nil
+	}
+	delete(t.dbs, path)
+	return shared.close()
+}
+
+type storage struct {
+	walletType    *Type
+	name          string
+	path          string
+	db            *db
+	freshnessTime time.Duration
+	closeOnce     sync.Once
+}
+
+func (s *storage) Set(key, value string) error {
+	if err := s.db.put(key, value); err != nil {
+		logger.Errorf("Error storing record in key/value wallet [%s]: %s", s.name, err)
+		return indyerror.New(indyerror.CommonIOError)
+	}
+	return nil
+}
+
+func (s *storage) Get(key string) (string, error) {
+	e, ok := s.db.get(key)
+	if !ok {
+		return "", indyerror.New(indyerror.WalletNotFoundError)
+	}
+	return e.value, nil
+}
+
+func (s *storage) GetNotExpired(key string) (string, error) {
+	e, ok := s.db.get(key)
+	if !ok {
+		return "", indyerror.New(indyerror.WalletNotFoundError)
+	}
+	if s.freshnessTime > 0 && time.Since(e.created) > s.freshnessTime {
+		return "", indyerror.New(indyerror.WalletNotFoundError)
+	}
+	return e.value, nil
+}
+
+func (s *storage) List(keyPrefix string) ([]wallet.Record, error) {
+	return s.db.list(keyPrefix), nil
+}
+
+func (s *storage) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.walletType.release(s.path)
+	})
+	return err
+}
+
+func dbPath(name, config string) (string, error) {
+	cfg, err := wallet.ParseConfig(config)
+	if err != nil {
+		logger.Warnf("Invalid config for key/value wallet [%s]: %s", name, err)
+		return "", indyerror.New(indyerror.CommonInvalidStructure)
+	}
+
+	// The name must not escape the directory
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		logger.Warnf("Invalid name for key/value wallet [%s]", name)
+		return "", indyerror.New(indyerror.CommonInvalidParam1)
+	}
+
+	dir := cfg.Dir
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".indy_client", "kv_wallet")
+	}
+	return filepath.Join(dir, name+fileExt), nil
+}
+
+func parseCredentials(credentials string) (*wallet.Credentials, error) {
+	creds, err := wallet.ParseCredentials(credentials)
+	if err != nil {
+		logger.Warnf("Invalid credentials for key/value wallet: %s", err)
+		return nil, indyerror.New(indyerror.CommonInvalidStructure)
+	}
+	if creds.Key == "" {
+		logger.Warnf("Credentials for key/value wallet must contain a key")
+		return nil, indyerror.New(indyerror.CommonInvalidStructure)
+	}
+	return creds, nil
+}
+
+// rekeyDB opens the database and re-encrypts it with the new passphrase. If the database
+// is already encrypted with the new passphrase, for example because a previous rekey
+// completed just before a crash, the database is opened with the new passphrase.
+func rekeyDB(path, passphrase, newPassphrase string) (*db, error) {
+	d, err := openDB(path, passphrase)
+	if err == errAccessFailed && verifyKey(path, newPassphrase) == nil {
+		logger.Infof("Wallet database [%s] is already encrypted with the new key", path)
+		return openDB(path, newPassphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.rekey(newPassphrase); err != nil {
+		d.close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// verifyKey checks the passphrase against the header of the database file
+func verifyKey(path, passphrase string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, _, err = readHeader(f, passphrase)
+	return err
+}
+
+// dbError converts an error opening a database into an Indy error
+func dbError(name string, err error) error {
+	if err == errAccessFailed {
+		return indyerror.New(indyerror.WalletAccessFailed)
+	}
+	logger.Errorf("Error opening key/value wallet [%s]: %s", name, err)
+	return indyerror.New(indyerror.CommonIOError)
+}
@@ -0,0 +1,348 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kv
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+const credentials = `{"key": "key1"}`
+
+func TestKVStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := fmt.Sprintf(`{"dir": "%s"}`, dir)
+	walletType := New()
+
+	if err := walletType.Create("wallet1", config, credentials); err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+	err = walletType.Create("wallet1", config, credentials)
+	if indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletAlreadyExistsError), err)
+	}
+
+	storage1, err := walletType.Open("wallet1", config, "", credentials)
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	storage2, err := walletType.Open("wallet1", config, `{"freshness_time": 1}`, credentials)
+	if err != nil {
+		t.Fatalf("Error received from second Open: %s", err)
+	}
+
+	_, err = walletType.Open("wallet1", config, "", `{"key": "invalid"}`)
+	if indyerror.Code(err) != indyerror.WalletAccessFailed {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletAccessFailed), err)
+	}
+
+	// Concurrent writes from both handles
+	var wg sync.WaitGroup
+	for i, s := range []interface {
+		Set(key, value string) error
+	}{storage1, storage2} {
+		wg.Add(1)
+		go func(i int, s interface {
+			Set(key, value string) error
+		}) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := s.Set(fmt.Sprintf("key%d::%d", i, j), fmt.Sprintf("value%d", j)); err != nil {
+					t.Errorf("Error received from Set: %s", err)
+				}
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	records, err := storage2.List("key1::")
+	if err != nil {
+		t.Fatalf("Error received from List: %s", err)
+	}
+	if len(records) != 50 {
+		t.Fatalf("Expecting 50 records but got %d", len(records))
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := storage2.GetNotExpired("key0::1"); indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting expired record but got [%v]", err)
+	}
+	if _, err := storage1.GetNotExpired("key0::1"); err != nil {
+		t.Fatalf("Error received from GetNotExpired: %s", err)
+	}
+
+	if err := walletType.Delete("wallet1", config, credentials); indyerror.Code(err) != indyerror.CommonInvalidState {
+		t.Fatalf("Expecting error deleting open wallet but got [%v]", err)
+	}
+
+	storage1.Close()
+	storage2.Close()
+
+	// Records must survive reopening
+	storage1, err = walletType.Open("wallet1", config, "", credentials)
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	value, err := storage1.Get("key1::49")
+	if err != nil {
+		t.Fatalf("Error received from Get: %s", err)
+	}
+	if value != "value49" {
+		t.Fatalf("Expecting [value49] but got [%s]", value)
+	}
+	storage1.Close()
+
+	if err := walletType.Delete("wallet1", config, credentials); err != nil {
+		t.Fatalf("Error received from Delete: %s", err)
+	}
+}
+
+func TestDBCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := dir + "/wallet1" + fileExt
+	if err := createDB(path, "key1"); err != nil {
+		t.Fatalf("Error received from createDB: %s", err)
+	}
+	d, err := openDB(path, "key1")
+	if err != nil {
+		t.Fatalf("Error received from openDB: %s", err)
+	}
+	for i := 0; i < 2*minCompactGarbage; i++ {
+		if err := d.put("key", fmt.Sprintf("value%d", i)); err != nil {
+			t.Fatalf("Error received from put: %s", err)
+		}
+	}
+	if d.garbage >= minCompactGarbage {
+		t.Fatalf("Expecting database to be compacted but it has %d superseded records", d.garbage)
+	}
+	d.close()
+
+	d, err = openDB(path, "key1")
+	if err != nil {
+		t.Fatalf("Error received from openDB: %s", err)
+	}
+	defer d.close()
+	e, ok := d.get("key")
+	if !ok || e.value != fmt.Sprintf("value%d", 2*minCompactGarbage-1) {
+		t.Fatalf("Unexpected value after compaction: %v", e)
+	}
+}
+
+func TestDBFailedAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := dir + "/wallet1" + fileExt
+	if err := createDB(path, "key1"); err != nil {
+		t.Fatalf("Error received from createDB: %s", err)
+	}
+	d, err := openDB(path, "key1")
+	if err != nil {
+		t.Fatalf("Error received from openDB: %s", err)
+	}
+	if err := d.put("key1", "value1"); err != nil {
+		t.Fatalf("Error received from put: %s", err)
+	}
+
+	// A partially written record is discarded so that later records can be read
+	offset, err := d.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		t.Fatalf("Error getting offset: %s", err)
+	}
+	if _, err := d.file.Write(frame([]byte("partial record"))[:10]); err != nil {
+		t.Fatalf("Error writing partial record: %s", err)
+	}
+	if err := d.truncate(offset); err != nil {
+		t.Fatalf("Error received from truncate: %s", err)
+	}
+	if err := d.put("key2", "value2"); err != nil {
+		t.Fatalf("Error received from put: %s", err)
+	}
+
+	// Writes are refused if the log can't be truncated after a failed write
+	writable := d.file
+	readOnly, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Error opening database read-only: %s", err)
+	}
+	d.file = readOnly
+	if err := d.put("key3", "value3"); err == nil {
+		t.Fatalf("Expecting error writing to read-only database")
+	}
+	if err := d.put("key3", "value3"); err == nil {
+		t.Fatalf("Expecting error writing after failed truncation")
+	}
+	writable.Close()
+	d.close()
+
+	d, err = openDB(path, "key1")
+	if err != nil {
+		t.Fatalf("Error received from openDB: %s", err)
+	}
+	defer d.close()
+	for _, kv := range [][2]string{{"key1", "value1"}, {"key2", "value2"}} {
+		e, ok := d.get(kv[0])
+		if !ok || e.value != kv[1] {
+			t.Fatalf("Expecting [%s] for key [%s] but got %v", kv[1], kv[0], e)
+		}
+	}
+	if _, ok := d.get("key3"); ok {
+		t.Fatalf("Expecting failed write not to be stored")
+	}
+}
+
+func TestKVRekey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := &wallet.Config{Dir: dir}
+	configJSON, _ := config.JSON()
+	walletType := New()
+
+	if err := walletType.Create("wallet1", configJSON, credentials); err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+	storage, err := walletType.Open("wallet1", configJSON, "", credentials)
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		storage.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i))
+	}
+
+	oldCreds := &wallet.Credentials{Key: "key1"}
+	newCreds := &wallet.Credentials{Key: "key2"}
+	err = wallet.Rekey(walletType, "wallet1", config, oldCreds, newCreds)
+	if indyerror.Code(err) != indyerror.CommonInvalidState {
+		t.Fatalf("Expecting error rekeying open wallet but got [%v]", err)
+	}
+	storage.Close()
+
+	if err := wallet.Rekey(walletType, "wallet1", config, oldCreds, newCreds); err != nil {
+		t.Fatalf("Error received from Rekey: %s", err)
+	}
+	// Repeating the rekey, e.g. after a crash, completes without error
+	if err := wallet.Rekey(walletType, "wallet1", config, oldCreds, newCreds); err != nil {
+		t.Fatalf("Error received from repeated Rekey: %s", err)
+	}
+
+	if _, err := walletType.Open("wallet1", configJSON, "", credentials); indyerror.Code(err) != indyerror.WalletAccessFailed {
+		t.Fatalf("Expecting error [%s] for old key but got [%v]", indyerror.New(indyerror.WalletAccessFailed), err)
+	}
+
+	// Rekey through the credentials passed to Open
+	storage, err = walletType.Open("wallet1", configJSON, "", `{"key": "key2", "rekey": "key3"}`)
+	if err != nil {
+		t.Fatalf("Error received from Open with rekey: %s", err)
+	}
+	storage.Close()
+
+	storage, err = walletType.Open("wallet1", configJSON, "", `{"key": "key3"}`)
+	if err != nil {
+		t.Fatalf("Error received from Open with new key: %s", err)
+	}
+	defer storage.Close()
+	records, err := storage.List("key")
+	if err != nil {
+		t.Fatalf("Error received from List: %s", err)
+	}
+	if len(records) != 10 {
+		t.Fatalf("Expecting 10 records after rekey but got %d", len(records))
+	}
+
+	if err := wallet.Rekey(&struct{ wallet.Type }{}, "wallet1", nil, oldCreds, newCreds); err == nil {
+		t.Fatalf("Expecting error for wallet type that doesn't support rekey")
+	}
+}
+
+func TestKVInvalidName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kvwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := fmt.Sprintf(`{"dir": "%s"}`, dir)
+	walletType := New()
+
+	for _, name := range []string{"", "../wallet1", "dir/wallet1", `dir\wallet1`, ".."} {
+		if err := walletType.Create(name, config, credentials); indyerror.Code(err) != indyerror.CommonInvalidParam1 {
+			t.Fatalf("Expecting error [%s] creating wallet [%s] but got [%v]", indyerror.New(indyerror.CommonInvalidParam1), name, err)
+		}
+		if err := walletType.Delete(name, config, credentials); indyerror.Code(err) != indyerror.CommonInvalidParam1 {
+			t.Fatalf("Expecting error [%s] deleting wallet [%s] but got [%v]", indyerror.New(indyerror.CommonInvalidParam1), name, err)
+		}
+	}
+}
+
+func TestKVDeleteLocked(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Locking across processes isn't supported on Windows")
+	}
+
+	dir, err := ioutil.TempDir("", "kvwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := fmt.Sprintf(`{"dir": "%s"}`, dir)
+	walletType := New()
+
+	if err := walletType.Create("wallet1", config, credentials); err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+
+	// A separate wallet type has its own handles, as another process would
+	otherType := New()
+	storage, err := otherType.Open("wallet1", config, "", credentials)
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+
+	if err := walletType.Delete("wallet1", config, credentials); indyerror.Code(err) != indyerror.CommonInvalidState {
+		t.Fatalf("Expecting error deleting wallet locked by another process but got [%v]", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wallet1"+fileExt)); err != nil {
+		t.Fatalf("Expecting database file to remain but got [%s]", err)
+	}
+
+	storage.Close()
+
+	if err := walletType.Delete("wallet1", config, credentials); err != nil {
+		t.Fatalf("Error received from Delete: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wallet1"+fileExt)); !os.IsNotExist(err) {
+		t.Fatalf("Expecting database file to be deleted but got [%v]", err)
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kv
+
+import "os"
+
+// lockFile opens the lock file. Locking across processes isn't supported on Windows.
+func lockFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+}
+
+func unlockFile(f *os.File) {
+	f.Close()
+}
+
+// syncDir is a no-op since directories can't be synced on Windows
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,341 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrate
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+// DefaultType is the name of libindy's built-in wallet type
+const DefaultType = "default"
+
+// selectDefaultRecords reads the records from the database of a 'default' wallet
+const selectDefaultRecords = `SELECT key, value FROM wallet ORDER BY key`
+
+// Endpoint is a wallet that is migrated. The wallets are accessed through libindy: the target
+// wallet is created with wallet.Create and the records are read and written through the storage
+// of the open wallet (see wallet.Wallet.Storage), so the target must be of a wallet type
+// registered with wallet.RegisterType.
+//
+// libindy doesn't provide access to the records of its built-in 'default' wallet type so the
+// records of a default source wallet are read from its SQLite database (see DB).
+type Endpoint struct {
+	// Name is the name of the wallet
+	Name string
+
+	// PoolName is the name of the pool of the target wallet. Defaults to the pool of the source wallet.
+	PoolName string
+
+	// Type is the name of the registered wallet type of the target wallet
+	Type string
+
+	// Config is the wallet configuration json of the target wallet
+	Config string
+
+	// RuntimeConfig is the runtime wallet configuration json
+	RuntimeConfig string
+
+	// Credentials is the wallet credentials json
+	Credentials string
+
+	// DB is the database of a source wallet of the 'default' type (see DefaultWalletPath). The
+	// database must be opened with a SQLite driver which supports SQLCipher if the wallet is
+	// encrypted.
+	DB *sql.DB
+}
+
+// Result is the result of a migration
+type Result struct {
+	// Records is the number of records that were (or, for a dry run, would be) migrated
+	Records int
+
+	// Checksum is the checksum of the migrated records (see Checksum)
+	Checksum string
+
+	// DryRun is true if the target wallet wasn't created
+	DryRun bool
+}
+
+// Migrate copies all records of the source wallet to the target wallet, which is created by
+// the migration and must not exist. The target is verified to contain the same number of records
+// with the same checksum as the source; if verification fails the target wallet is deleted.
+// The source wallet isn't changed.
+//
+// For a dry run the records of the source are read and counted but the target isn't created.
+//
+// The wallets are opened in libindy by the migration so they must not be open while they are
+// migrated. The records are stored with the time of the migration so their freshness
+// (see wallet.RuntimeConfig) starts over.
+func Migrate(source, target *Endpoint, dryRun bool) (*Result, error) {
+	if err := validate(source, "source"); err != nil {
+		return nil, err
+	}
+	if err := validateTarget(target); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Migrating wallet [%s] to [%s] - Dry run: %t", source.Name, target.Name, dryRun)
+
+	info, err := lookup(source.Name)
+	if err != nil {
+		return nil, err
+	}
+	records, err := read(source, info)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Records:  len(records),
+		Checksum: Checksum(records),
+		DryRun:   dryRun,
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	t := *target
+	if t.PoolName == "" {
+		t.PoolName = info.PoolName
+	}
+	if _, err := Write(&t, records); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Migrated %d records from wallet [%s] to [%s]", result.Records, source.Name, target.Name)
+	return result, nil
+}
+
+// Read returns all records of the wallet, which must exist
+func Read(e *Endpoint) ([]wallet.Record, error) {
+	if err := validate(e, "source"); err != nil {
+		return nil, err
+	}
+	info, err := lookup(e.Name)
+	if err != nil {
+		return nil, err
+	}
+	return read(e, info)
+}
+
+// Write creates the wallet, which must not exist, and stores the given records in it. The wallet
+// is verified to contain the records; if writing or verification fails the wallet is deleted.
+func Write(e *Endpoint, records []wallet.Record) (*Result, error) {
+	if err := validateTarget(e); err != nil {
+		return nil, err
+	}
+	if e.PoolName == "" {
+		return nil, fmt.Errorf("target pool name must be specified")
+	}
+
+	exists, err := wallet.Exists(e.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("wallet [%s] already exists", e.Name)
+	}
+
+	result := &Result{
+		Records:  len(records),
+		Checksum: Checksum(records),
+	}
+
+	if err := wallet.Create(e.PoolName, e.Name, e.Type, e.Config, e.Credentials); err != nil {
+		return nil, fmt.Errorf("error creating wallet [%s]: %s", e.Name, err)
+	}
+	if err := write(e, records, result); err != nil {
+		deleteTarget(e)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Verify checks that the wallet contains the given number of records with the given checksum
+func Verify(e *Endpoint, count int, checksum string) error {
+	records, err := Read(e)
+	if err != nil {
+		return err
+	}
+	return verify(e.Name, records, count, checksum)
+}
+
+// DefaultWalletPath returns the path of the SQLite database of the wallet with the given name
+// of libindy's built-in 'default' wallet type
+func DefaultWalletPath(name string) string {
+	home := os.Getenv("HOME")
+	if home == "" {
+		home = "/home/indy"
+	}
+	return filepath.Join(home, ".indy_client", "wallet", name, "sqlite.db")
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the given records. The checksum
+// doesn't depend on the order of the records.
+func Checksum(records []wallet.Record) string {
+	sorted := make([]wallet.Record, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+
+	h := sha256.New()
+	var length [8]byte
+	for _, r := range sorted {
+		// Length prefixes so that the boundary between key and value is unambiguous
+		binary.BigEndian.PutUint64(length[:], uint64(len(r.Key)))
+		h.Write(length[:])
+		h.Write([]byte(r.Key))
+		binary.BigEndian.PutUint64(length[:], uint64(len(r.Value)))
+		h.Write(length[:])
+		h.Write([]byte(r.Value))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func validate(e *Endpoint, name string) error {
+	if e == nil || e.Name == "" {
+		return fmt.Errorf("%s wallet name must be specified", name)
+	}
+	return nil
+}
+
+func validateTarget(e *Endpoint) error {
+	if err := validate(e, "target"); err != nil {
+		return err
+	}
+	if e.Type == "" {
+		return fmt.Errorf("target wallet type must be specified")
+	}
+	if e.Type == DefaultType {
+		return fmt.Errorf("records can't be written to wallets of the '%s' type", DefaultType)
+	}
+	return nil
+}
+
+// lookup returns the info of the wallet with the given name or an error if the wallet doesn't exist
+func lookup(name string) (*wallet.Info, error) {
+	infos, err := wallet.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if info.Name == name {
+			return info, nil
+		}
+	}
+	return nil, fmt.Errorf("wallet [%s] doesn't exist", name)
+}
+
+func read(e *Endpoint, info *wallet.Info) ([]wallet.Record, error) {
+	var records []wallet.Record
+	var err error
+	if info.Type == DefaultType {
+		records, err = readDefault(e)
+	} else {
+		records, err = readOpen(e)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading wallet [%s]: %s", e.Name, err)
+	}
+	return records, nil
+}
+
+func readOpen(e *Endpoint) ([]wallet.Record, error) {
+	w, err := wallet.Open(e.Name, e.RuntimeConfig, e.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	storage, err := w.Storage()
+	if err != nil {
+		return nil, err
+	}
+	return storage.List("")
+}
+
+func readDefault(e *Endpoint) ([]wallet.Record, error) {
+	if e.DB == nil {
+		return nil, fmt.Errorf("database of '%s' wallet must be specified", DefaultType)
+	}
+
+	rows, err := e.DB.Query(selectDefaultRecords)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []wallet.Record
+	for rows.Next() {
+		var r wallet.Record
+		if err := rows.Scan(&r.Key, &r.Value); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+func write(e *Endpoint, records []wallet.Record, result *Result) error {
+	w, err := wallet.Open(e.Name, e.RuntimeConfig, e.Credentials)
+	if err != nil {
+		return fmt.Errorf("error opening wallet [%s]: %s", e.Name, err)
+	}
+
+	err = writeOpen(w, records, result)
+	if closeErr := w.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("error closing wallet [%s]: %s", e.Name, closeErr)
+	}
+	return err
+}
+
+func writeOpen(w *wallet.Wallet, records []wallet.Record, result *Result) error {
+	storage, err := w.Storage()
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := storage.Set(r.Key, r.Value); err != nil {
+			return fmt.Errorf("error writing wallet [%s]: %s", w.Name, err)
+		}
+	}
+
+	written, err := storage.List("")
+	if err != nil {
+		return fmt.Errorf("error reading wallet [%s]: %s", w.Name, err)
+	}
+	return verify(w.Name, written, result.Records, result.Checksum)
+}
+
+func verify(name string, records []wallet.Record, count int, checksum string) error {
+	if len(records) != count {
+		return fmt.Errorf("wallet [%s] contains %d records but %d were expected", name, len(records), count)
+	}
+	if sum := Checksum(records); sum != checksum {
+		return fmt.Errorf("checksum of wallet [%s] is %s but %s was expected", name, sum, checksum)
+	}
+	return nil
+}
+
+func deleteTarget(e *Endpoint) {
+	if err := wallet.Delete(e.Name, e.Credentials); err != nil {
+		logger.Warnf("Error deleting wallet [%s] after failed write: %s", e.Name, err)
+	}
+}
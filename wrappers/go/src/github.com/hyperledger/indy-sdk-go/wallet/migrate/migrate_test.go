@@ -0,0 +1,326 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrate
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/wallet"
+	"github.com/hyperledger/indy-sdk-go/wallet/inmem"
+	"github.com/hyperledger/indy-sdk-go/wallet/kv"
+)
+
+const (
+	inmemTypeName = "migrate_inmem"
+	kvTypeName    = "migrate_kv"
+	lossyTypeName = "migrate_lossy"
+)
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+	lossy        = &lossyType{Type: inmem.New()}
+)
+
+func TestMigrate(t *testing.T) {
+	registerTypes(t)
+
+	dir, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := &Endpoint{Name: "migrate_source1"}
+	createSource(t, source.Name, 100)
+
+	target := &Endpoint{
+		Name:        "migrate_target1",
+		Type:        kvTypeName,
+		Config:      fmt.Sprintf(`{"dir": "%s"}`, dir),
+		Credentials: `{"key": "key1"}`,
+	}
+	deleteWallet(target.Name, target.Credentials)
+	defer deleteWallet(target.Name, target.Credentials)
+
+	result, err := Migrate(source, target, true)
+	if err != nil {
+		t.Fatalf("Error received from dry run: %s", err)
+	}
+	if !result.DryRun || result.Records != 100 {
+		t.Fatalf("Unexpected dry run result: %+v", result)
+	}
+	if exists, _ := wallet.Exists(target.Name); exists {
+		t.Fatalf("Expecting dry run not to create the target wallet")
+	}
+
+	migrated, err := Migrate(source, target, false)
+	if err != nil {
+		t.Fatalf("Error received from Migrate: %s", err)
+	}
+	if migrated.Records != 100 || migrated.Checksum != result.Checksum {
+		t.Fatalf("Unexpected migration result: %+v", migrated)
+	}
+	if err := Verify(target, 100, result.Checksum); err != nil {
+		t.Fatalf("Error received from Verify: %s", err)
+	}
+
+	// The migrated wallet is a regular libindy wallet
+	w, err := wallet.Open(target.Name, "", target.Credentials)
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	storage, err := w.Storage()
+	if err != nil {
+		t.Fatalf("Error received from Storage: %s", err)
+	}
+	if value, err := storage.Get("key42"); err != nil || value != "value42" {
+		t.Fatalf("Expecting value [value42] but got [%s]: %v", value, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+
+	// The target must not exist
+	if _, err := Migrate(source, target, false); err == nil {
+		t.Fatalf("Expecting error migrating to an existing wallet")
+	}
+
+	// The source must exist
+	if _, err := Migrate(&Endpoint{Name: "migrate_missing"}, &Endpoint{Name: "migrate_target2", Type: inmemTypeName}, true); err == nil {
+		t.Fatalf("Expecting error migrating a wallet that doesn't exist")
+	}
+	if exists, _ := wallet.Exists("migrate_missing"); exists {
+		t.Fatalf("Expecting missing source not to be created")
+	}
+}
+
+func TestMigrateVerificationFailure(t *testing.T) {
+	registerTypes(t)
+
+	source := &Endpoint{Name: "migrate_source2"}
+	createSource(t, source.Name, 2)
+
+	target := &Endpoint{Name: "migrate_target3", Type: lossyTypeName}
+	deleteWallet(target.Name, "")
+
+	if _, err := Migrate(source, target, false); err == nil {
+		t.Fatalf("Expecting verification of lossy target to fail")
+	}
+	if exists, _ := wallet.Exists(target.Name); exists {
+		t.Fatalf("Expecting target to be deleted after failed verification")
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	if err := validateTarget(&Endpoint{Name: "wallet1"}); err == nil {
+		t.Fatalf("Expecting error for missing wallet type")
+	}
+	if err := validateTarget(&Endpoint{Name: "wallet1", Type: DefaultType}); err == nil {
+		t.Fatalf("Expecting error for target of the default type")
+	}
+	if err := validateTarget(&Endpoint{Type: kvTypeName}); err == nil {
+		t.Fatalf("Expecting error for missing wallet name")
+	}
+	if err := validateTarget(&Endpoint{Name: "wallet1", Type: kvTypeName}); err != nil {
+		t.Fatalf("Error received from validateTarget: %s", err)
+	}
+}
+
+func TestDefaultWalletPath(t *testing.T) {
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+
+	os.Setenv("HOME", "/home/user1")
+	if path := DefaultWalletPath("wallet1"); path != "/home/user1/.indy_client/wallet/wallet1/sqlite.db" {
+		t.Fatalf("Unexpected wallet path [%s]", path)
+	}
+}
+
+func TestReadDefault(t *testing.T) {
+	db, err := sql.Open(defaultDriverName, "")
+	if err != nil {
+		t.Fatalf("Error opening database: %s", err)
+	}
+	defer db.Close()
+
+	info := &wallet.Info{Name: "wallet1", PoolName: "pool1", Type: DefaultType}
+	records, err := read(&Endpoint{Name: "wallet1", DB: db}, info)
+	if err != nil {
+		t.Fatalf("Error received from read: %s", err)
+	}
+	if len(records) != len(defaultRecords) || Checksum(records) != Checksum(defaultRecords) {
+		t.Fatalf("Unexpected records: %v", records)
+	}
+
+	if _, err := read(&Endpoint{Name: "wallet1"}, info); err == nil {
+		t.Fatalf("Expecting error reading default wallet without database")
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	a := Checksum([]wallet.Record{{Key: "key1", Value: "value1"}, {Key: "key2", Value: "value2"}})
+	b := Checksum([]wallet.Record{{Key: "key2", Value: "value2"}, {Key: "key1", Value: "value1"}})
+	if a != b {
+		t.Fatalf("Expecting checksum to be independent of order")
+	}
+	if c := Checksum([]wallet.Record{{Key: "key1valu", Value: "e1"}, {Key: "key2", Value: "value2"}}); c == a {
+		t.Fatalf("Expecting different checksum for different records")
+	}
+}
+
+// registerTypes registers the wallet types of the tests, which may only be registered once per process
+func registerTypes(t *testing.T) {
+	registerOnce.Do(func() {
+		if registerErr = wallet.RegisterType(inmemTypeName, inmem.New()); registerErr != nil {
+			return
+		}
+		if registerErr = wallet.RegisterType(kvTypeName, kv.New()); registerErr != nil {
+			return
+		}
+		registerErr = wallet.RegisterType(lossyTypeName, lossy)
+	})
+	if registerErr != nil {
+		t.Fatalf("Error received from RegisterType: %s", registerErr)
+	}
+}
+
+// createSource creates an in-memory wallet with the given number of records
+func createSource(t *testing.T, name string, count int) {
+	deleteWallet(name, "")
+	if err := wallet.Create("pool1", name, inmemTypeName, "", ""); err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+	w, err := wallet.Open(name, "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	defer w.Close()
+
+	storage, err := w.Storage()
+	if err != nil {
+		t.Fatalf("Error received from Storage: %s", err)
+	}
+	for i := 0; i < count; i++ {
+		if err := storage.Set(fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i)); err != nil {
+			t.Fatalf("Error received from Set: %s", err)
+		}
+	}
+}
+
+// deleteWallet deletes a wallet that was created by a previous run
+func deleteWallet(name, credentials string) {
+	if exists, _ := wallet.Exists(name); exists {
+		wallet.Delete(name, credentials)
+	}
+}
+
+// lossyType drops every other record that is stored
+type lossyType struct {
+	*inmem.Type
+}
+
+func (t *lossyType) Open(name, config, runtimeConfig, credentials string) (wallet.Storage, error) {
+	s, err := t.Type.Open(name, config, runtimeConfig, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &lossyStorage{Storage: s}, nil
+}
+
+type lossyStorage struct {
+	wallet.Storage
+	n int
+}
+
+func (s *lossyStorage) Set(key, value string) error {
+	s.n++
+	if s.n%2 == 0 {
+		return nil
+	}
+	return s.Storage.Set(key, value)
+}
+
+// The stand-in database below holds the records of a 'default' wallet and implements only
+// the query issued by readDefault so that the test doesn't depend on an external SQL driver.
+
+const defaultDriverName = "migrate-default-test"
+
+var defaultRecords = []wallet.Record{{Key: "key1", Value: "value1"}, {Key: "key2", Value: "value2"}}
+
+func init() {
+	sql.Register(defaultDriverName, defaultDriver{})
+}
+
+type defaultDriver struct{}
+
+func (defaultDriver) Open(name string) (driver.Conn, error) {
+	return defaultConn{}, nil
+}
+
+type defaultConn struct{}
+
+func (defaultConn) Prepare(query string) (driver.Stmt, error) {
+	if query != selectDefaultRecords {
+		return nil, fmt.Errorf("unsupported statement: %s", query)
+	}
+	return defaultStmt{}, nil
+}
+
+func (defaultConn) Close() error {
+	return nil
+}
+
+func (defaultConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions aren't supported")
+}
+
+type defaultStmt struct{}
+
+func (defaultStmt) Close() error {
+	return nil
+}
+
+func (defaultStmt) NumInput() int {
+	return 0
+}
+
+func (defaultStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("exec isn't supported")
+}
+
+func (defaultStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &defaultRows{records: defaultRecords}, nil
+}
+
+type defaultRows struct {
+	records []wallet.Record
+}
+
+func (r *defaultRows) Columns() []string {
+	return []string{"key", "value"}
+}
+
+func (r *defaultRows) Close() error {
+	return nil
+}
+
+func (r *defaultRows) Next(dest []driver.Value) error {
+	if len(r.records) == 0 {
+		return io.EOF
+	}
+	dest[0], dest[1] = r.records[0].Key, r.records[0].Value
+	r.records = r.records[1:]
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build sqlite
+// +build sqlite
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migrate
+
+// These tests read the database of a 'default' wallet and require the SQLite driver:
+//
+//   go get github.com/mattn/go-sqlite3
+//   go test -tags sqlite ./wallet/migrate/
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/wallet"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSQLiteReadDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "sqlite.db"))
+	if err != nil {
+		t.Fatalf("Error opening database: %s", err)
+	}
+	defer db.Close()
+
+	// The schema of libindy's default wallet type
+	if _, err := db.Exec("CREATE TABLE wallet (key TEXT CONSTRAINT constraint_name PRIMARY KEY, value TEXT NOT NULL, time_created TEXT NOT_NULL)"); err != nil {
+		t.Fatalf("Error creating table: %s", err)
+	}
+	var expected []wallet.Record
+	for i := 0; i < 10; i++ {
+		r := wallet.Record{Key: fmt.Sprintf("key%d", i), Value: fmt.Sprintf("value%d", i)}
+		if _, err := db.Exec("INSERT INTO wallet (key, value, time_created) VALUES (?, ?, ?)", r.Key, r.Value, "2018-01-01"); err != nil {
+			t.Fatalf("Error inserting record: %s", err)
+		}
+		expected = append(expected, r)
+	}
+
+	records, err := readDefault(&Endpoint{Name: "wallet1", DB: db})
+	if err != nil {
+		t.Fatalf("Error received from readDefault: %s", err)
+	}
+	if len(records) != len(expected) || Checksum(records) != Checksum(expected) {
+		t.Fatalf("Unexpected records: %v", records)
+	}
+
+	if _, err := readDefault(&Endpoint{Name: "wallet1"}); err == nil {
+		t.Fatalf("Expecting error reading default wallet without database")
+	}
+}
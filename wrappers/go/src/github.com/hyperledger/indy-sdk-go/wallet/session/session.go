@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package session
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+// ErrManagerClosed is returned by Acquire after the manager was closed
+var ErrManagerClosed = fmt.Errorf("wallet session manager is closed")
+
+// Manager keeps at most one open wallet per name and shares it between concurrent users.
+// Wallets are opened on demand with the credentials from a credentials provider and
+// are closed once they haven't been used for the idle timeout.
+type Manager struct {
+	provider    wallet.CredentialsProvider
+	idleTimeout time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]*entry
+	closing map[string]*entry
+	closed  bool
+	done    chan struct{}
+
+	// evicting tracks the evicted wallets that are being closed
+	evicting sync.WaitGroup
+
+	// open and close are replaced in unit tests
+	open  func(name string, credentials *wallet.Credentials) (*wallet.Wallet, error)
+	close func(w *wallet.Wallet) error
+}
+
+type entry struct {
+	name     string
+	ready    chan struct{}
+	closed   chan struct{}
+	wallet   *wallet.Wallet
+	err      error
+	refs     int
+	lastUsed time.Time
+}
+
+// Session is the use of an open wallet. Release must be called when the wallet is no longer used.
+type Session struct {
+	manager     *Manager
+	entry       *entry
+	releaseOnce sync.Once
+}
+
+// NewManager returns a new wallet session manager. Wallets that aren't used by any session
+// are closed after the given idle timeout. A timeout of zero closes wallets as soon as the
+// last session is released.
+func NewManager(provider wallet.CredentialsProvider, idleTimeout time.Duration) (*Manager, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("credentials provider must be specified")
+	}
+
+	m := &Manager{
+		provider:    provider,
+		idleTimeout: idleTimeout,
+		entries:     make(map[string]*entry),
+		closing:     make(map[string]*entry),
+		done:        make(chan struct{}),
+		open:        openWallet,
+		close:       (*wallet.Wallet).Close,
+	}
+	if idleTimeout > 0 {
+		go m.evictLoop()
+	}
+	return m, nil
+}
+
+// Acquire returns a session for the wallet with the given name, opening the wallet if necessary.
+// Concurrent calls for the same wallet wait for a single open.
+func (m *Manager) Acquire(name string) (*Session, error) {
+	if name == "" {
+		return nil, fmt.Errorf("wallet name must be specified")
+	}
+
+	m.mutex.Lock()
+	if m.closed {
+		m.mutex.Unlock()
+		return nil, ErrManagerClosed
+	}
+	e, ok := m.entries[name]
+	var closing *entry
+	if !ok {
+		e = &entry{name: name, ready: make(chan struct{}), closed: make(chan struct{})}
+		m.entries[name] = e
+		closing = m.closing[name]
+	}
+	e.refs++
+	m.mutex.Unlock()
+
+	if !ok {
+		if closing != nil {
+			// libindy can't open the wallet again until the evicted instance is closed
+			<-closing.closed
+		}
+		e.wallet, e.err = m.openWallet(name)
+		if e.err != nil {
+			m.mutex.Lock()
+			if m.entries[name] == e {
+				delete(m.entries, name)
+			}
+			m.mutex.Unlock()
+		}
+		close(e.ready)
+	}
+
+	<-e.ready
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &Session{manager: m, entry: e}, nil
+}
+
+// OpenCount returns the number of wallets that are currently open
+func (m *Manager) OpenCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.entries)
+}
+
+// Close closes all wallets, including the wallets that are still in use, and waits
+// for evicted wallets to be closed. Subsequent calls to Acquire fail.
+func (m *Manager) Close() error {
+	m.mutex.Lock()
+	if m.closed {
+		m.mutex.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	entries := m.entries
+	m.entries = make(map[string]*entry)
+	m.mutex.Unlock()
+
+	var firstErr error
+	for _, e := range entries {
+		<-e.ready
+		if e.err != nil {
+			continue
+		}
+		if err := m.closeWallet(e); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	// No wallets are evicted once the manager is closed
+	m.evicting.Wait()
+	return firstErr
+}
+
+// Wallet returns the open wallet
+func (s *Session) Wallet() *wallet.Wallet {
+	return s.entry.wallet
+}
+
+// Release releases the session. The wallet is closed after the idle timeout unless
+// it's acquired again. Subsequent calls have no effect.
+func (s *Session) Release() {
+	s.releaseOnce.Do(func() {
+		s.manager.release(s.entry)
+	})
+}
+
+func (m *Manager) openWallet(name string) (*wallet.Wallet, error) {
+	credentials, err := m.provider.Credentials(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials of wallet [%s]: %s", name, err)
+	}
+
+	logger.Debugf("Opening wallet [%s] for session", name)
+	w, err := m.open(name, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (m *Manager) closeWallet(e *entry) error {
+	logger.Debugf("Closing wallet [%s]", e.name)
+	err := m.close(e.wallet)
+	if err != nil {
+		logger.Warnf("Error closing wallet [%s]: %s", e.name, err)
+	}
+
+	m.mutex.Lock()
+	if m.closing[e.name] == e {
+		delete(m.closing, e.name)
+	}
+	m.mutex.Unlock()
+	close(e.closed)
+
+	return err
+}
+
+func (m *Manager) release(e *entry) {
+	m.mutex.Lock()
+	e.refs--
+	e.lastUsed = time.Now()
+	evict := e.refs == 0 && m.idleTimeout <= 0 && m.entries[e.name] == e
+	if evict {
+		m.evict(e)
+	}
+	m.mutex.Unlock()
+
+	if evict {
+		m.closeWallet(e)
+		m.evicting.Done()
+	}
+}
+
+func (m *Manager) evictLoop() {
+	interval := m.idleTimeout / 2
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes the wallets that haven't been used since the idle timeout
+func (m *Manager) evictIdle(now time.Time) {
+	var idle []*entry
+
+	m.mutex.Lock()
+	for _, e := range m.entries {
+		if e.refs == 0 && now.Sub(e.lastUsed) >= m.idleTimeout {
+			idle = append(idle, e)
+			m.evict(e)
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, e := range idle {
+		m.closeWallet(e)
+		m.evicting.Done()
+	}
+}
+
+// evict removes the entry from the open wallets. The wallet must subsequently be closed
+// with closeWallet, followed by m.evicting.Done(). The caller must hold the lock.
+func (m *Manager) evict(e *entry) {
+	delete(m.entries, e.name)
+	m.closing[e.name] = e
+	m.evicting.Add(1)
+}
+
+func openWallet(name string, credentials *wallet.Credentials) (*wallet.Wallet, error) {
+	return wallet.OpenWithConfig(name, nil, credentials)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package session
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+type mockWallets struct {
+	mutex  sync.Mutex
+	open   map[string]bool
+	opened int
+	closed int
+}
+
+func newManager(t *testing.T, idleTimeout time.Duration) (*Manager, *mockWallets) {
+	provider := wallet.CredentialsProviderFunc(func(walletName string) (*wallet.Credentials, error) {
+		if walletName == "unknown" {
+			return nil, fmt.Errorf("no credentials")
+		}
+		return &wallet.Credentials{Key: "key-" + walletName}, nil
+	})
+
+	mock := &mockWallets{open: make(map[string]bool)}
+	m, err := NewManager(provider, idleTimeout)
+	if err != nil {
+		t.Fatalf("Error received from NewManager: %s", err)
+	}
+	m.open = func(name string, credentials *wallet.Credentials) (*wallet.Wallet, error) {
+		// Simulate a slow open
+		time.Sleep(10 * time.Millisecond)
+
+		mock.mutex.Lock()
+		defer mock.mutex.Unlock()
+		if credentials.Key != "key-"+name {
+			return nil, fmt.Errorf("invalid credentials")
+		}
+		if mock.open[name] {
+			return nil, fmt.Errorf("wallet [%s] is already open", name)
+		}
+		mock.open[name] = true
+		mock.opened++
+		return &wallet.Wallet{Name: name}, nil
+	}
+	m.close = func(w *wallet.Wallet) error {
+		mock.mutex.Lock()
+		defer mock.mutex.Unlock()
+		mock.open[w.Name] = false
+		mock.closed++
+		return nil
+	}
+	return m, mock
+}
+
+func (m *mockWallets) counts() (int, int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.opened, m.closed
+}
+
+func TestManager(t *testing.T) {
+	m, mock := newManager(t, 50*time.Millisecond)
+	defer m.Close()
+
+	// Concurrent sessions share a single open wallet
+	var wg sync.WaitGroup
+	sessions := make(chan *Session, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := m.Acquire("wallet1")
+			if err != nil {
+				t.Errorf("Error received from Acquire: %s", err)
+				return
+			}
+			sessions <- s
+		}()
+	}
+	wg.Wait()
+	close(sessions)
+
+	if opened, _ := mock.counts(); opened != 1 {
+		t.Fatalf("Expecting wallet to be opened once but it was opened %d times", opened)
+	}
+
+	for s := range sessions {
+		if s.Wallet().Name != "wallet1" {
+			t.Fatalf("Unexpected wallet: %s", s.Wallet().Name)
+		}
+		s.Release()
+		s.Release()
+	}
+
+	// The wallet stays open while it's in use
+	s, err := m.Acquire("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Acquire: %s", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, closed := mock.counts(); closed != 0 {
+		t.Fatalf("Expecting wallet in use to stay open")
+	}
+	s.Release()
+
+	time.Sleep(150 * time.Millisecond)
+	if _, closed := mock.counts(); closed != 1 {
+		t.Fatalf("Expecting idle wallet to be closed")
+	}
+	if n := m.OpenCount(); n != 0 {
+		t.Fatalf("Expecting no open wallets but got %d", n)
+	}
+
+	// The wallet is reopened on demand
+	s, err = m.Acquire("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Acquire: %s", err)
+	}
+	s.Release()
+	if opened, _ := mock.counts(); opened != 2 {
+		t.Fatalf("Expecting wallet to be reopened")
+	}
+
+	if _, err := m.Acquire("unknown"); err == nil {
+		t.Fatalf("Expecting error for wallet without credentials")
+	}
+	if n := m.OpenCount(); n != 1 {
+		t.Fatalf("Expecting 1 open wallet but got %d", n)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+	if opened, closed := mock.counts(); opened != closed {
+		t.Fatalf("Expecting all wallets to be closed: opened %d, closed %d", opened, closed)
+	}
+	if _, err := m.Acquire("wallet1"); err != ErrManagerClosed {
+		t.Fatalf("Expecting error [%s] but got [%v]", ErrManagerClosed, err)
+	}
+}
+
+func TestManagerNoIdleTimeout(t *testing.T) {
+	m, mock := newManager(t, 0)
+	defer m.Close()
+
+	// Acquiring the wallet right after it was released must wait for it to be closed
+	for i := 0; i < 20; i++ {
+		s, err := m.Acquire("wallet1")
+		if err != nil {
+			t.Fatalf("Error received from Acquire: %s", err)
+		}
+		go s.Release()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if opened, closed := mock.counts(); opened == 0 || opened != closed {
+		t.Fatalf("Expecting wallet to be closed after each open: opened %d, closed %d", opened, closed)
+	}
+}
+
+func TestManagerCloseWaitsForEviction(t *testing.T) {
+	m, mock := newManager(t, 0)
+
+	closing := make(chan struct{})
+	proceed := make(chan struct{})
+	closeWallet := m.close
+	m.close = func(w *wallet.Wallet) error {
+		close(closing)
+		<-proceed
+		return closeWallet(w)
+	}
+
+	s, err := m.Acquire("wallet1")
+	if err != nil {
+		t.Fatalf("Error received from Acquire: %s", err)
+	}
+	go s.Release()
+	<-closing
+
+	closed := make(chan error)
+	go func() {
+		closed <- m.Close()
+	}()
+
+	select {
+	case <-closed:
+		t.Fatalf("Expecting Close to wait for the evicted wallet to be closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(proceed)
+	if err := <-closed; err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+	if opened, closed := mock.counts(); opened != 1 || closed != 1 {
+		t.Fatalf("Expecting wallet to be closed: opened %d, closed %d", opened, closed)
+	}
+}
+
+func TestNewManagerNoProvider(t *testing.T) {
+	if _, err := NewManager(nil, time.Minute); err == nil {
+		t.Fatalf("Expecting error for nil credentials provider")
+	}
+}
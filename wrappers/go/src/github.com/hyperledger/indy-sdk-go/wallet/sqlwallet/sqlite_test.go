@@ -0,0 +1,103 @@
+//go:build sqlite
+// +build sqlite
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sqlwallet
+
+// These tests run the tests of sqlwallet_test.go against SQLite and require the SQLite driver:
+//
+//   go get github.com/mattn/go-sqlite3
+//   go test -tags sqlite ./wallet/sqlwallet/
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSQLiteStorage(t *testing.T) {
+	dir, db := openTestDB(t)
+	defer os.RemoveAll(dir)
+	defer db.Close()
+
+	testStorage(t, db)
+}
+
+func TestSQLiteConcurrentMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "wallets.db")
+
+	// Each instance has its own connection pool as if it ran in a separate service
+	const instances = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, instances)
+	for i := 0; i < instances; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db, err := sql.Open("sqlite3", path+"?_busy_timeout=10000")
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer db.Close()
+			_, err = New(db, QuestionPlaceholder)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Error received from concurrent New: %s", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Error opening database: %s", err)
+	}
+	defer db.Close()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM indy_wallet_migrations`).Scan(&count); err != nil {
+		t.Fatalf("Error querying migrations: %s", err)
+	}
+	if count != len(migrations) {
+		t.Fatalf("Expecting %d migrations to be applied once but got %d", len(migrations), count)
+	}
+}
+
+func TestSQLiteFailedMigration(t *testing.T) {
+	dir, db := openTestDB(t)
+	defer os.RemoveAll(dir)
+	defer db.Close()
+
+	testFailedMigration(t, db)
+}
+
+func openTestDB(t *testing.T) (string, *sql.DB) {
+	dir, err := ioutil.TempDir("", "sqlwallet")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "wallets.db")+"?_busy_timeout=10000")
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Error opening database: %s", err)
+	}
+	return dir, db
+}
@@ -0,0 +1,356 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sqlwallet
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+	"github.com/hyperledger/indy-sdk-go/common/logging"
+	"github.com/hyperledger/indy-sdk-go/wallet"
+)
+
+var logger = logging.MustGetLogger("indy-sdk")
+
+const (
+	// TypeName is the default name under which the SQL wallet type is registered
+	TypeName = "sql"
+)
+
+// Placeholder is the style of the bind parameters used by the SQL driver
+type Placeholder int
+
+const (
+	// QuestionPlaceholder uses '?' for bind parameters (SQLite)
+	QuestionPlaceholder Placeholder = iota
+
+	// DollarPlaceholder uses '$1', '$2', ... for bind parameters (PostgreSQL)
+	DollarPlaceholder
+)
+
+// The statements are written with '?' placeholders and rebound for the configured Placeholder
+const (
+	createMigrationsTable = `CREATE TABLE IF NOT EXISTS indy_wallet_migrations (version INTEGER NOT NULL PRIMARY KEY)`
+	selectMigration       = `SELECT COALESCE(MAX(version), 0) FROM indy_wallet_migrations`
+	insertMigration       = `INSERT INTO indy_wallet_migrations (version) VALUES (?)`
+
+	insertWallet  = `INSERT INTO indy_wallets (name, created) VALUES (?, ?)`
+	countWallet   = `SELECT COUNT(*) FROM indy_wallets WHERE name = ?`
+	deleteWallet  = `DELETE FROM indy_wallets WHERE name = ?`
+	deleteRecords = `DELETE FROM indy_wallet_records WHERE wallet = ?`
+	updateRecord  = `UPDATE indy_wallet_records SET record_value = ?, created = ? WHERE wallet = ? AND record_key = ?`
+	insertRecord  = `INSERT INTO indy_wallet_records (wallet, record_key, record_value, created) VALUES (?, ?, ?, ?)`
+	selectRecord  = `SELECT record_value, created FROM indy_wallet_records WHERE wallet = ? AND record_key = ?`
+	listRecords   = `SELECT record_key, record_value FROM indy_wallet_records WHERE wallet = ? AND SUBSTR(record_key, 1, LENGTH(?)) = ?`
+)
+
+// migrations contains the schema changes. migrations[i] upgrades the schema to version i+1.
+// Existing migrations must never be changed; add a new migration instead.
+var migrations = [][]string{
+	{
+		`CREATE TABLE indy_wallets (
+			name VARCHAR(255) NOT NULL PRIMARY KEY,
+			created BIGINT NOT NULL
+		)`,
+		`CREATE TABLE indy_wallet_records (
+			wallet VARCHAR(255) NOT NULL,
+			record_key VARCHAR(1024) NOT NULL,
+			record_value TEXT NOT NULL,
+			created BIGINT NOT NULL,
+			PRIMARY KEY (wallet, record_key)
+		)`,
+	},
+}
+
+// Type is a wallet type that stores the records of all wallets in a shared SQL database.
+// Each record is stored with the name of the wallet it belongs to so that any number of
+// service instances may use the same tables.
+//
+// The supported databases are SQLite and PostgreSQL. Wallet names are limited to 255 and
+// record keys to 1024 characters. MySQL isn't supported since the primary key of the records
+// exceeds its index length limit.
+//
+// The values are stored as given by libindy. Use database level encryption if the
+// records must be encrypted at rest.
+type Type struct {
+	db          *sql.DB
+	placeholder Placeholder
+}
+
+// New returns a new SQL wallet type backed by the given database and migrates the
+// schema to the latest version. The type must be registered (see wallet.RegisterType)
+// before wallets of the type may be created.
+//
+// Service instances sharing the database may call New concurrently: each migration is applied
+// in a transaction and a migration that fails because another instance applied it is skipped.
+// With SQLite, configure a busy timeout so that concurrent migrations wait for each other.
+func New(db *sql.DB, placeholder Placeholder) (*Type, error) {
+	t := &Type{
+		db:          db,
+		placeholder: placeholder,
+	}
+	if err := t.migrate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Create creates the wallet
+func (t *Type) Create(name, config, credentials string) error {
+	logger.Debugf("Creating SQL wallet [%s]", name)
+
+	if _, err := t.db.Exec(t.bind(insertWallet), name, now()); err != nil {
+		exists, existsErr := t.exists(name)
+		if existsErr == nil && exists {
+			return indyerror.New(indyerror.WalletAlreadyExistsError)
+		}
+		return t.dbError(name, err)
+	}
+	return nil
+}
+
+// Open opens the wallet.
+//
+// runtimeConfig Runtime configuration json (see wallet.RuntimeConfig).
+func (t *Type) Open(name, config, runtimeConfig, credentials string) (wallet.Storage, error) {
+	logger.Debugf("Opening SQL wallet [%s]", name)
+
+	rc, err := wallet.ParseRuntimeConfig(runtimeConfig)
+	if err != nil {
+		logger.Warnf("Invalid runtime config for SQL wallet [%s]: %s", name, err)
+		return nil, indyerror.New(indyerror.CommonInvalidStructure)
+	}
+
+	exists, err := t.exists(name)
+	if err != nil {
+		return nil, t.dbError(name, err)
+	}
+	if !exists {
+		logger.Errorf("SQL wallet [%s] doesn't exist", name)
+		return nil, indyerror.New(indyerror.CommonInvalidState)
+	}
+
+	return &storage{
+		walletType:    t,
+		name:          name,
+		freshnessTime: rc.Freshness(),
+	}, nil
+}
+
+// Delete deletes the wallet and all of its records
+func (t *Type) Delete(name, config, credentials string) error {
+	logger.Debugf("Deleting SQL wallet [%s]", name)
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return t.dbError(name, err)
+	}
+	if _, err := tx.Exec(t.bind(deleteRecords), name); err != nil {
+		tx.Rollback()
+		return t.dbError(name, err)
+	}
+	if _, err := tx.Exec(t.bind(deleteWallet), name); err != nil {
+		tx.Rollback()
+		return t.dbError(name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return t.dbError(name, err)
+	}
+	return nil
+}
+
+// migrateMutex serializes the migrations of the wallet types of this process
+var migrateMutex sync.Mutex
+
+func (t *Type) migrate() error {
+	migrateMutex.Lock()
+	defer migrateMutex.Unlock()
+
+	if _, err := t.db.Exec(createMigrationsTable); err != nil {
+		// Another instance may have created the table concurrently
+		if _, verErr := t.version(); verErr != nil {
+			return fmt.Errorf("error creating migrations table: %s", err)
+		}
+	}
+
+	version, err := t.version()
+	if err != nil {
+		return fmt.Errorf("error querying schema version: %s", err)
+	}
+
+	for version < len(migrations) {
+		logger.Infof("Migrating SQL wallet schema to version %d", version+1)
+
+		if err := t.applyMigration(version); err != nil {
+			// The migration fails if another instance applied it concurrently
+			current, verErr := t.version()
+			if verErr != nil || current <= version {
+				return fmt.Errorf("error migrating schema to version %d: %s", version+1, err)
+			}
+			logger.Infof("SQL wallet schema was migrated to version %d by another instance", current)
+			version = current
+			continue
+		}
+		version++
+	}
+	return nil
+}
+
+// applyMigration upgrades the schema from the given version to the next in a transaction
+func (t *Type) applyMigration(version int) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range migrations[version] {
+		if _, err := tx.Exec(stmt); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	// The primary key on the version makes concurrent migrations to the same version fail
+	if _, err := tx.Exec(t.bind(insertMigration), version+1); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *Type) version() (int, error) {
+	var version int
+	if err := t.db.QueryRow(selectMigration).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+func (t *Type) exists(name string) (bool, error) {
+	var count int
+	if err := t.db.QueryRow(t.bind(countWallet), name).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// bind rewrites the '?' placeholders of the statement for the configured placeholder style
+func (t *Type) bind(stmt string) string {
+	if t.placeholder != DollarPlaceholder {
+		return stmt
+	}
+
+	var b bytes.Buffer
+	n := 0
+	for _, c := range stmt {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+		} else {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func (t *Type) dbError(name string, err error) error {
+	logger.Errorf("Database error for SQL wallet [%s]: %s", name, err)
+	return indyerror.New(indyerror.CommonIOError)
+}
+
+type storage struct {
+	walletType    *Type
+	name          string
+	freshnessTime time.Duration
+}
+
+func (s *storage) Set(key, value string) error {
+	t := s.walletType
+
+	// An upsert isn't portable so update the record and insert it if it doesn't exist yet.
+	// If another instance inserts the record concurrently the insert fails and the update is retried.
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := t.db.Exec(t.bind(updateRecord), value, now(), s.name, key)
+		if err != nil {
+			return t.dbError(s.name, err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			return nil
+		}
+		if _, err = t.db.Exec(t.bind(insertRecord), s.name, key, value, now()); err == nil {
+			return nil
+		}
+		logger.Debugf("Error inserting record into SQL wallet [%s]: %s", s.name, err)
+	}
+	return t.dbError(s.name, fmt.Errorf("unable to store record [%s]", key))
+}
+
+func (s *storage) Get(key string) (string, error) {
+	value, _, err := s.get(key)
+	return value, err
+}
+
+func (s *storage) GetNotExpired(key string) (string, error) {
+	value, created, err := s.get(key)
+	if err != nil {
+		return "", err
+	}
+	if s.freshnessTime > 0 && time.Since(time.Unix(0, created*int64(time.Millisecond))) > s.freshnessTime {
+		return "", indyerror.New(indyerror.WalletNotFoundError)
+	}
+	return value, nil
+}
+
+func (s *storage) List(keyPrefix string) ([]wallet.Record, error) {
+	t := s.walletType
+
+	// The prefix is compared with SUBSTR since LIKE is case-insensitive in SQLite
+	rows, err := t.db.Query(t.bind(listRecords), s.name, keyPrefix, keyPrefix)
+	if err != nil {
+		return nil, t.dbError(s.name, err)
+	}
+	defer rows.Close()
+
+	var records []wallet.Record
+	for rows.Next() {
+		var record wallet.Record
+		if err := rows.Scan(&record.Key, &record.Value); err != nil {
+			return nil, t.dbError(s.name, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, t.dbError(s.name, err)
+	}
+	return records, nil
+}
+
+func (s *storage) Close() error {
+	return nil
+}
+
+func (s *storage) get(key string) (string, int64, error) {
+	t := s.walletType
+
+	var value string
+	var created int64
+	err := t.db.QueryRow(t.bind(selectRecord), s.name, key).Scan(&value, &created)
+	if err == sql.ErrNoRows {
+		return "", 0, indyerror.New(indyerror.WalletNotFoundError)
+	}
+	if err != nil {
+		return "", 0, t.dbError(s.name, err)
+	}
+	return value, created, nil
+}
+
+// now returns the current time in milliseconds
+func now() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
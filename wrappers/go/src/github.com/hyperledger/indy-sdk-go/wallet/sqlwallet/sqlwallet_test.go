@@ -0,0 +1,523 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sqlwallet
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
+)
+
+// The tests run against the stand-in database below. sqlite_test.go runs the same
+// tests against SQLite if the sqlite build tag is set.
+
+func TestSQLStorage(t *testing.T) {
+	db := openMemDB(t, "storage")
+	defer db.Close()
+
+	testStorage(t, db)
+}
+
+func TestConcurrentMigration(t *testing.T) {
+	db := openMemDB(t, "concurrent")
+	defer db.Close()
+
+	// Another instance applies the migrations after this instance read the schema version
+	memDBs.get("concurrent").staleVersions = 1
+	if _, err := New(db, QuestionPlaceholder); err != nil {
+		t.Fatalf("Error received from New: %s", err)
+	}
+	if _, err := New(db, QuestionPlaceholder); err != nil {
+		t.Fatalf("Error received from New: %s", err)
+	}
+	if n := len(memDBs.get("concurrent").migrations); n != len(migrations) {
+		t.Fatalf("Expecting %d migrations to be applied once but got %d", len(migrations), n)
+	}
+}
+
+func TestFailedMigration(t *testing.T) {
+	db := openMemDB(t, "failed")
+	defer db.Close()
+
+	testFailedMigration(t, db)
+}
+
+func TestBind(t *testing.T) {
+	walletType := &Type{placeholder: DollarPlaceholder}
+	stmt := walletType.bind(updateRecord)
+	expected := `UPDATE indy_wallet_records SET record_value = $1, created = $2 WHERE wallet = $3 AND record_key = $4`
+	if stmt != expected {
+		t.Fatalf("Expecting [%s] but got [%s]", expected, stmt)
+	}
+}
+
+func testStorage(t *testing.T, db *sql.DB) {
+	walletType, err := New(db, QuestionPlaceholder)
+	if err != nil {
+		t.Fatalf("Error received from New: %s", err)
+	}
+	// Migrating an up-to-date schema must be a no-op
+	if _, err := New(db, QuestionPlaceholder); err != nil {
+		t.Fatalf("Error received from second New: %s", err)
+	}
+	if version, err := walletType.version(); err != nil || version != len(migrations) {
+		t.Fatalf("Expecting schema version %d but got %d (%v)", len(migrations), version, err)
+	}
+
+	for _, name := range []string{"wallet1", "wallet2"} {
+		if err := walletType.Create(name, "", ""); err != nil {
+			t.Fatalf("Error received from Create: %s", err)
+		}
+	}
+	err = walletType.Create("wallet1", "", "")
+	if indyerror.Code(err) != indyerror.WalletAlreadyExistsError {
+		t.Fatalf("Expecting error [%s] but got [%v]", indyerror.New(indyerror.WalletAlreadyExistsError), err)
+	}
+	if _, err := walletType.Open("wallet3", "", "", ""); indyerror.Code(err) != indyerror.CommonInvalidState {
+		t.Fatalf("Expecting error opening unknown wallet but got [%v]", err)
+	}
+
+	storage1, err := walletType.Open("wallet1", "", `{"freshness_time": 1}`, "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	storage2, err := walletType.Open("wallet2", "", "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+
+	for _, kv := range [][2]string{{"key1::a", "value1"}, {"key1::b", "value2"}, {"key1%c", "value3"}, {"key1!_d", "value4"}, {"key2::a", "value5"}, {"KEY1::c", "value6"}} {
+		if err := storage1.Set(kv[0], kv[1]); err != nil {
+			t.Fatalf("Error received from Set: %s", err)
+		}
+	}
+	if err := storage1.Set("key1::a", "value7"); err != nil {
+		t.Fatalf("Error received from Set: %s", err)
+	}
+	if err := storage2.Set("key1::a", "other"); err != nil {
+		t.Fatalf("Error received from Set: %s", err)
+	}
+
+	value, err := storage1.Get("key1::a")
+	if err != nil {
+		t.Fatalf("Error received from Get: %s", err)
+	}
+	if value != "value7" {
+		t.Fatalf("Expecting [value7] but got [%s]", value)
+	}
+	if _, err := storage2.Get("key1::b"); indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting records to be separated by wallet but got [%v]", err)
+	}
+
+	// Prefixes are case-sensitive and LIKE wildcards have no special meaning
+	for prefix, expected := range map[string]string{
+		"key1::": "[key1::a key1::b]",
+		"KEY1::": "[KEY1::c]",
+		"key1%":  "[key1%c]",
+		"key1!_": "[key1!_d]",
+		"key1_":  "[]",
+		"":       "[KEY1::c key1!_d key1%c key1::a key1::b key2::a]",
+	} {
+		records, err := storage1.List(prefix)
+		if err != nil {
+			t.Fatalf("Error received from List: %s", err)
+		}
+		keys := []string{}
+		for _, record := range records {
+			keys = append(keys, record.Key)
+		}
+		sort.Strings(keys)
+		if fmt.Sprint(keys) != expected {
+			t.Fatalf("Expecting records %s for prefix [%s] but got %v", expected, prefix, keys)
+		}
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := storage1.GetNotExpired("key1::b"); indyerror.Code(err) != indyerror.WalletNotFoundError {
+		t.Fatalf("Expecting expired record but got [%v]", err)
+	}
+	if _, err := storage2.GetNotExpired("key1::a"); err != nil {
+		t.Fatalf("Error received from GetNotExpired: %s", err)
+	}
+
+	storage1.Close()
+	storage2.Close()
+
+	if err := walletType.Delete("wallet1", "", ""); err != nil {
+		t.Fatalf("Error received from Delete: %s", err)
+	}
+	if n := countRecords(t, db, "wallet1"); n != 0 {
+		t.Fatalf("Expecting records of deleted wallet to be removed but %d remain", n)
+	}
+	if n := countRecords(t, db, "wallet2"); n != 1 {
+		t.Fatalf("Expecting 1 record in wallet2 but got %d", n)
+	}
+	if exists, err := walletType.exists("wallet1"); err != nil || exists {
+		t.Fatalf("Expecting wallet1 to be deleted but exists: %t (%v)", exists, err)
+	}
+}
+
+func testFailedMigration(t *testing.T, db *sql.DB) {
+	walletType, err := New(db, QuestionPlaceholder)
+	if err != nil {
+		t.Fatalf("Error received from New: %s", err)
+	}
+
+	saved := migrations
+	defer func() { migrations = saved }()
+	migrations = append(migrations[:len(migrations):len(migrations)], []string{
+		createTestTable,
+		`INSERT INTO indy_wallet_missing (id) VALUES (1)`,
+	})
+
+	if _, err := New(db, QuestionPlaceholder); err == nil {
+		t.Fatalf("Expecting error from failed migration")
+	}
+	if version, err := walletType.version(); err != nil || version != len(saved) {
+		t.Fatalf("Expecting schema version %d but got %d (%v)", len(saved), version, err)
+	}
+	if _, err := db.Exec(countTestTable); err == nil {
+		t.Fatalf("Expecting failed migration to be rolled back")
+	}
+}
+
+const (
+	countRecordsQuery = `SELECT COUNT(*) FROM indy_wallet_records WHERE wallet = ?`
+	createTestTable   = `CREATE TABLE indy_wallet_test (id INTEGER NOT NULL)`
+	countTestTable    = `SELECT COUNT(*) FROM indy_wallet_test`
+)
+
+func countRecords(t *testing.T, db *sql.DB, walletName string) int {
+	var count int
+	if err := db.QueryRow(countRecordsQuery, walletName).Scan(&count); err != nil {
+		t.Fatalf("Error counting records: %s", err)
+	}
+	return count
+}
+
+// The stand-in database below implements only the statements issued by the wallet type
+// and the tests so that the tests don't depend on an external SQL driver.
+
+const driverName = "sqlwallet-test"
+
+var memDBs = &memDriver{dbs: make(map[string]*memDB)}
+
+func init() {
+	sql.Register(driverName, memDBs)
+}
+
+// openMemDB opens a new, empty stand-in database
+func openMemDB(t *testing.T, name string) *sql.DB {
+	memDBs.mutex.Lock()
+	memDBs.dbs[name] = newMemDB()
+	memDBs.mutex.Unlock()
+
+	db, err := sql.Open(driverName, name)
+	if err != nil {
+		t.Fatalf("Error opening database: %s", err)
+	}
+	return db
+}
+
+// memDriver is a database/sql driver whose data source names are the names of stand-in databases
+type memDriver struct {
+	mutex sync.Mutex
+	dbs   map[string]*memDB
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) {
+	db := d.get(name)
+	if db == nil {
+		return nil, fmt.Errorf("unknown database [%s]", name)
+	}
+	return &memConn{db: db}, nil
+}
+
+func (d *memDriver) get(name string) *memDB {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.dbs[name]
+}
+
+type memRecord struct {
+	value   string
+	created int64
+}
+
+// memState is the contents of a stand-in database
+type memState struct {
+	migrations map[int64]bool
+	tables     map[string]bool
+	wallets    map[string]int64
+	records    map[[2]string]memRecord
+}
+
+func (s *memState) copy() *memState {
+	c := &memState{
+		migrations: make(map[int64]bool),
+		tables:     make(map[string]bool),
+		wallets:    make(map[string]int64),
+		records:    make(map[[2]string]memRecord),
+	}
+	for k, v := range s.migrations {
+		c.migrations[k] = v
+	}
+	for k, v := range s.tables {
+		c.tables[k] = v
+	}
+	for k, v := range s.wallets {
+		c.wallets[k] = v
+	}
+	for k, v := range s.records {
+		c.records[k] = v
+	}
+	return c
+}
+
+type memDB struct {
+	mutex sync.Mutex
+	*memState
+
+	// staleVersions is the number of schema version queries that return version 0
+	staleVersions int
+}
+
+func newMemDB() *memDB {
+	return &memDB{memState: (&memState{}).copy()}
+}
+
+func (d *memDB) begin() *memState {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.memState.copy()
+}
+
+// rollback restores the state saved by begin. This is sufficient for the tests since
+// transactions don't run concurrently with other statements.
+func (d *memDB) rollback(saved *memState) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.memState = saved
+}
+
+func (d *memDB) exec(query string, args []driver.Value) (int64, [][]driver.Value, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	switch query {
+	case createMigrationsTable:
+		return 0, nil, nil
+	case migrations[0][0]:
+		return d.createTable("indy_wallets")
+	case migrations[0][1]:
+		return d.createTable("indy_wallet_records")
+	case createTestTable:
+		return d.createTable("indy_wallet_test")
+	case countTestTable:
+		if !d.tables["indy_wallet_test"] {
+			return 0, nil, fmt.Errorf("no such table: indy_wallet_test")
+		}
+		return 0, [][]driver.Value{{int64(0)}}, nil
+	case selectMigration:
+		var max int64
+		if d.staleVersions > 0 {
+			d.staleVersions--
+			// The migrations were applied by another instance
+			for i := range migrations {
+				d.migrations[int64(i+1)] = true
+			}
+			d.tables["indy_wallets"] = true
+			d.tables["indy_wallet_records"] = true
+		} else {
+			for v := range d.migrations {
+				if v > max {
+					max = v
+				}
+			}
+		}
+		return 0, [][]driver.Value{{max}}, nil
+	case insertMigration:
+		version := args[0].(int64)
+		if d.migrations[version] {
+			return 0, nil, fmt.Errorf("duplicate key")
+		}
+		d.migrations[version] = true
+		return 1, nil, nil
+	}
+
+	if !d.tables["indy_wallets"] || !d.tables["indy_wallet_records"] {
+		return 0, nil, fmt.Errorf("no such table")
+	}
+
+	switch query {
+	case insertWallet:
+		name := args[0].(string)
+		if _, ok := d.wallets[name]; ok {
+			return 0, nil, fmt.Errorf("duplicate key")
+		}
+		d.wallets[name] = args[1].(int64)
+		return 1, nil, nil
+	case countWallet:
+		_, ok := d.wallets[args[0].(string)]
+		if ok {
+			return 0, [][]driver.Value{{int64(1)}}, nil
+		}
+		return 0, [][]driver.Value{{int64(0)}}, nil
+	case deleteWallet:
+		name := args[0].(string)
+		if _, ok := d.wallets[name]; !ok {
+			return 0, nil, nil
+		}
+		delete(d.wallets, name)
+		return 1, nil, nil
+	case deleteRecords:
+		var n int64
+		for k := range d.records {
+			if k[0] == args[0].(string) {
+				delete(d.records, k)
+				n++
+			}
+		}
+		return n, nil, nil
+	case updateRecord:
+		k := [2]string{args[2].(string), args[3].(string)}
+		if _, ok := d.records[k]; !ok {
+			return 0, nil, nil
+		}
+		d.records[k] = memRecord{value: args[0].(string), created: args[1].(int64)}
+		return 1, nil, nil
+	case insertRecord:
+		k := [2]string{args[0].(string), args[1].(string)}
+		if _, ok := d.records[k]; ok {
+			return 0, nil, fmt.Errorf("duplicate key")
+		}
+		d.records[k] = memRecord{value: args[2].(string), created: args[3].(int64)}
+		return 1, nil, nil
+	case selectRecord:
+		r, ok := d.records[[2]string{args[0].(string), args[1].(string)}]
+		if !ok {
+			return 0, nil, nil
+		}
+		return 0, [][]driver.Value{{r.value, r.created}}, nil
+	case listRecords:
+		var rows [][]driver.Value
+		for k, r := range d.records {
+			if k[0] == args[0].(string) && strings.HasPrefix(k[1], args[2].(string)) {
+				rows = append(rows, []driver.Value{k[1], r.value})
+			}
+		}
+		return 0, rows, nil
+	case countRecordsQuery:
+		var n int64
+		for k := range d.records {
+			if k[0] == args[0].(string) {
+				n++
+			}
+		}
+		return 0, [][]driver.Value{{n}}, nil
+	}
+	return 0, nil, fmt.Errorf("unsupported statement: %s", query)
+}
+
+func (d *memDB) createTable(name string) (int64, [][]driver.Value, error) {
+	if d.tables[name] {
+		return 0, nil, fmt.Errorf("table %s already exists", name)
+	}
+	d.tables[name] = true
+	return 0, nil, nil
+}
+
+type memConn struct {
+	db *memDB
+}
+
+func (c *memConn) Prepare(query string) (driver.Stmt, error) {
+	return &memStmt{db: c.db, query: query}, nil
+}
+
+func (c *memConn) Close() error {
+	return nil
+}
+
+func (c *memConn) Begin() (driver.Tx, error) {
+	return &memTx{db: c.db, saved: c.db.begin()}, nil
+}
+
+type memTx struct {
+	db    *memDB
+	saved *memState
+}
+
+func (tx *memTx) Commit() error {
+	return nil
+}
+
+func (tx *memTx) Rollback() error {
+	tx.db.rollback(tx.saved)
+	return nil
+}
+
+type memStmt struct {
+	db    *memDB
+	query string
+}
+
+func (s *memStmt) Close() error {
+	return nil
+}
+
+func (s *memStmt) NumInput() int {
+	return -1
+}
+
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	n, _, err := s.db.exec(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(n), nil
+}
+
+func (s *memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	_, rows, err := s.db.exec(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &memRows{rows: rows}, nil
+}
+
+type memRows struct {
+	rows [][]driver.Value
+}
+
+func (r *memRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"a", "b"}
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *memRows) Close() error {
+	return nil
+}
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -7,7 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package wallet
 
 import (
+	"encoding/json"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hyperledger/indy-sdk-go/common/callback"
 	"github.com/hyperledger/indy-sdk-go/common/logging"
@@ -17,14 +21,99 @@ import (
 
 var logger = logging.MustGetLogger("indy-sdk")
 
+// ErrClosed is returned when a wallet is used after it was closed
+var ErrClosed = fmt.Errorf("wallet is closed")
+
+// openStorage holds the storage of the open wallets of custom wallet types by wallet name
+var openStorage = struct {
+	sync.RWMutex
+	storage map[string]*storageAdapter
+}{storage: make(map[string]*storageAdapter)}
+
+// warnUnclosed is set to 1 if a warning should be logged for wallets that are garbage collected while open
+var warnUnclosed int32
+
 // Wallet is the wallet
 type Wallet struct {
 	Name   string `json:"pool"`
 	handle types.Handle
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// SetFinalizerWarnings enables or disables a warning that is logged when a wallet is garbage
+// collected without being closed. The setting applies to wallets opened subsequently.
+func SetFinalizerWarnings(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&warnUnclosed, 1)
+	} else {
+		atomic.StoreInt32(&warnUnclosed, 0)
+	}
 }
 
-// Type is a custom wallet type
+// Type is a custom wallet type implemented in Go. It mirrors the create, open and
+// delete handlers of indy_register_wallet_type. The functions are invoked by libindy
+// from its own threads so implementations must be safe for concurrent use.
+//
+// Errors returned by the implementation should be Indy errors (see indyerror.New), for example
+// WalletNotFoundError when a key doesn't exist. Any other error is reported to libindy as
+// CommonInvalidState.
 type Type interface {
+	// Create creates a new wallet with the given name
+	Create(name, config, credentials string) error
+
+	// Open opens the wallet with the given name
+	Open(name, config, runtimeConfig, credentials string) (Storage, error)
+
+	// Delete deletes the wallet with the given name
+	Delete(name, config, credentials string) error
+}
+
+// Rekeyer is implemented by custom wallet types that encrypt their records with a key from the
+// wallet credentials. Rekey must re-encrypt all records atomically so that a crash leaves the
+// wallet encrypted either with the old or with the new key, and calling Rekey again with the
+// same credentials after a crash must complete the rekey.
+type Rekeyer interface {
+	// Rekey re-encrypts all records of the closed wallet with the key in newCredentials
+	Rekey(name, config, credentials, newCredentials string) error
+}
+
+// Storage is an open wallet of a custom wallet type. It mirrors the set, get,
+// get-not-expired, list and close handlers of indy_register_wallet_type.
+type Storage interface {
+	// Set stores the value for the given key
+	Set(key, value string) error
+
+	// Get returns the value for the given key
+	Get(key string) (string, error)
+
+	// GetNotExpired returns the value for the given key if the value
+	// is still fresh according to the runtime configuration of the wallet
+	GetNotExpired(key string) (string, error)
+
+	// List returns all records whose key starts with the given prefix
+	List(keyPrefix string) ([]Record, error)
+
+	// Close closes the wallet
+	Close() error
+}
+
+// Info describes a wallet that was created with Create
+type Info struct {
+	// Name is the name of the wallet
+	Name string `json:"name"`
+
+	// PoolName is the name of the pool associated with the wallet
+	PoolName string `json:"associated_pool_name"`
+
+	// Type is the wallet type, e.g. 'default' or the name of a registered type
+	Type string `json:"type"`
+}
+
+// Record is a key/value record stored in a wallet
+type Record struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 // Create creates a new secure wallet with the given unique name.
@@ -60,17 +149,196 @@ func Open(name, config, credentials string) (wallet *Wallet, err error) {
 	return
 }
 
-// RegisterType registers a custome wallet implementation.
+// CreateWithConfig creates a new secure wallet with the given unique name using a typed
+// config and credentials. The config and credentials are optional.
+func CreateWithConfig(poolName, name, walletType string, config *Config, credentials *Credentials) error {
+	var configJSON string
+	if config != nil {
+		var err error
+		if configJSON, err = config.JSON(); err != nil {
+			return err
+		}
+	}
+	credsJSON, err := credentialsJSON(credentials)
+	if err != nil {
+		return err
+	}
+	return Create(poolName, name, walletType, configJSON, credsJSON)
+}
+
+// OpenWithConfig opens the wallet with specific name using a typed runtime config and
+// credentials. The runtime config and credentials are optional.
+func OpenWithConfig(name string, runtimeConfig *RuntimeConfig, credentials *Credentials) (*Wallet, error) {
+	var runtimeConfigJSON string
+	if runtimeConfig != nil {
+		var err error
+		if runtimeConfigJSON, err = runtimeConfig.JSON(); err != nil {
+			return nil, err
+		}
+	}
+	credsJSON, err := credentialsJSON(credentials)
+	if err != nil {
+		return nil, err
+	}
+	return Open(name, runtimeConfigJSON, credsJSON)
+}
+
+// DeleteWithCredentials deletes the given wallet using typed credentials. The credentials are optional.
+func DeleteWithCredentials(name string, credentials *Credentials) error {
+	credsJSON, err := credentialsJSON(credentials)
+	if err != nil {
+		return err
+	}
+	return Delete(name, credsJSON)
+}
+
+// CreateWithProvider creates a new secure wallet with the given unique name using
+// the credentials from the given provider. The config is optional.
+func CreateWithProvider(poolName, name, walletType string, config *Config, provider CredentialsProvider) error {
+	credentials, err := provide(name, provider)
+	if err != nil {
+		return err
+	}
+	return CreateWithConfig(poolName, name, walletType, config, credentials)
+}
+
+// OpenWithProvider opens the wallet with specific name using the credentials from the
+// given provider. The runtime config is optional.
+func OpenWithProvider(name string, runtimeConfig *RuntimeConfig, provider CredentialsProvider) (*Wallet, error) {
+	credentials, err := provide(name, provider)
+	if err != nil {
+		return nil, err
+	}
+	return OpenWithConfig(name, runtimeConfig, credentials)
+}
+
+// DeleteWithProvider deletes the given wallet using the credentials from the given provider
+func DeleteWithProvider(name string, provider CredentialsProvider) error {
+	credentials, err := provide(name, provider)
+	if err != nil {
+		return err
+	}
+	return DeleteWithCredentials(name, credentials)
+}
+
+// Rekey re-encrypts all records of a wallet of the given Go wallet type with the key in
+// newCredentials. The wallet type must implement Rekeyer and the wallet must be closed.
+// If Rekey fails, for example due to a crash, it may be called again with the same
+// credentials to complete the rekey. The config is optional.
+func Rekey(walletType Type, name string, config *Config, credentials, newCredentials *Credentials) error {
+	rekeyer, ok := walletType.(Rekeyer)
+	if !ok {
+		return fmt.Errorf("wallet type doesn't support rekey")
+	}
+	if name == "" {
+		return fmt.Errorf("wallet name must be specified")
+	}
+	if credentials == nil || newCredentials == nil {
+		return fmt.Errorf("current and new credentials must be specified")
+	}
+
+	var configJSON string
+	if config != nil {
+		var err error
+		if configJSON, err = config.JSON(); err != nil {
+			return err
+		}
+	}
+	credsJSON, err := credentials.JSON()
+	if err != nil {
+		return err
+	}
+	newCredsJSON, err := newCredentials.JSON()
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Rekeying wallet [%s]", name)
+	return rekeyer.Rekey(name, configJSON, credsJSON, newCredsJSON)
+}
+
+// List returns the wallets that were created with Create
+func List() ([]*Info, error) {
+	infoChan, errChan := list()
+	select {
+	case infos := <-infoChan:
+		return infos, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
+// Exists returns true if a wallet with the given name was created
+func Exists(name string) (bool, error) {
+	infos, err := List()
+	if err != nil {
+		return false, err
+	}
+	for _, info := range infos {
+		if info.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RegisterType registers a custom wallet implementation.
+// Wallets of the registered type are created by passing typeName to Create.
+//
+// typeName   Name of the wallet type.
+// walletType The Go implementation of the wallet type.
 func RegisterType(typeName string, walletType Type) error {
 	return <-registerType(typeName, walletType)
 }
 
-// Handle returns the Indy handle to the wallet
+// Handle returns the Indy handle to the wallet. The handle is returned even if the
+// wallet is closed; use OpenHandle to check that the wallet is still open.
 func (w *Wallet) Handle() types.Handle {
 	return w.handle
 }
 
-// Close closes the wallet
+// OpenHandle returns the Indy handle to the wallet or ErrClosed if the wallet is closed
+func (w *Wallet) OpenHandle() (types.Handle, error) {
+	if w == nil {
+		return 0, fmt.Errorf("wallet must be specified")
+	}
+
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	return w.handle, nil
+}
+
+// Storage returns the storage of the open wallet, which must be of a custom wallet type
+// registered with RegisterType. It provides access to all records of the wallet, for example
+// to back it up or to migrate it. The storage must not be closed by the caller; it's closed
+// by libindy when the wallet is closed.
+func (w *Wallet) Storage() (Storage, error) {
+	if _, err := w.OpenHandle(); err != nil {
+		return nil, err
+	}
+
+	openStorage.RLock()
+	defer openStorage.RUnlock()
+
+	adapter := openStorage.storage[w.Name]
+	if adapter == nil {
+		return nil, fmt.Errorf("storage of wallet [%s] isn't accessible since it isn't of a registered wallet type", w.Name)
+	}
+	return adapter.Storage, nil
+}
+
+// IsClosed returns true if the wallet was closed
+func (w *Wallet) IsClosed() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.closed
+}
+
+// Close closes the wallet. Closing a closed wallet has no effect.
 func (w *Wallet) Close() error {
 	return <-w.close()
 }
@@ -79,17 +347,49 @@ func (w *Wallet) close() chan error {
 	logger.Debugf("Closing wallet [%s]...", w.Name)
 
 	errChan := make(chan error, 1)
-	err := indy.CloseWallet(w.handle, callback.New(errChan))
+
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		errChan <- nil
+		return errChan
+	}
+	w.closed = true
+	w.mutex.Unlock()
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			logger.Debugf("Error closing wallet [%s]: %s", w.Name, err)
+			w.reopen()
+		}
+		errChan <- err
+	}
+
+	err := indy.CloseWallet(w.handle, cb)
 	if err != nil {
 		// Send the error immediately
+		w.reopen()
 		errChan <- err
 	}
 
 	return errChan
 }
 
+// reopen marks the wallet as open after closing it failed
+func (w *Wallet) reopen() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.closed = false
+}
+
+func warnIfOpen(w *Wallet) {
+	if !w.IsClosed() {
+		logger.Warnf("Wallet [%s] was garbage collected without being closed", w.Name)
+	}
+}
+
 func create(poolName, name, walletType, config, credentials string) chan error {
-	logger.Debugf("Creating wallet: %s, Pool [%s], Type [%s], Config [%s], Credentials [%s]", name, poolName, walletType, config, credentials)
+	logger.Debugf("Creating wallet: %s, Pool [%s], Type [%s], Config [%s], Credentials [%s]", name, poolName, walletType, config, redactedCredentials(credentials))
 
 	errChan := make(chan error, 1)
 
@@ -112,7 +412,7 @@ func create(poolName, name, walletType, config, credentials string) chan error {
 }
 
 func delete(name, credentials string) chan error {
-	logger.Debugf("Deleting wallet [%s] - Credentials [%s]", name, credentials)
+	logger.Debugf("Deleting wallet [%s] - Credentials [%s]", name, redactedCredentials(credentials))
 
 	errChan := make(chan error, 1)
 
@@ -131,7 +431,7 @@ func delete(name, credentials string) chan error {
 }
 
 func open(name, config, credentials string) (chan *Wallet, chan error) {
-	logger.Debugf("Opening wallet: %s, Config [%s], Credentials [%s]", name, config, credentials)
+	logger.Debugf("Opening wallet: %s, Config [%s], Credentials [%s]", name, config, redactedCredentials(credentials))
 
 	errChan := make(chan error, 1)
 	walletChan := make(chan *Wallet)
@@ -147,11 +447,14 @@ func open(name, config, credentials string) (chan *Wallet, chan error) {
 			errChan <- err
 		} else {
 			logger.Debugf("Successfully opened wallet ledger [%s]", name)
-			handle := data.(types.Handle)
-			walletChan <- &Wallet{
+			w := &Wallet{
 				Name:   name,
-				handle: handle,
+				handle: data.(types.Handle),
+			}
+			if atomic.LoadInt32(&warnUnclosed) == 1 {
+				runtime.SetFinalizer(w, warnIfOpen)
 			}
+			walletChan <- w
 		}
 	}
 
@@ -164,6 +467,63 @@ func open(name, config, credentials string) (chan *Wallet, chan error) {
 	return walletChan, errChan
 }
 
+func list() (chan []*Info, chan error) {
+	logger.Debugf("Listing wallets...")
+
+	infoChan := make(chan []*Info)
+	errChan := make(chan error, 1)
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			errChan <- err
+		} else {
+			json := data.(string)
+			logger.Debugf("Wallet list: %s", json)
+			infos, err := asInfos(json)
+			if err != nil {
+				errChan <- fmt.Errorf("invalid wallet list: %s", err)
+			} else {
+				infoChan <- infos
+			}
+		}
+	}
+
+	err := indy.ListWallets(cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
+
+	return infoChan, errChan
+}
+
+func provide(name string, provider CredentialsProvider) (*Credentials, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("credentials provider must be specified")
+	}
+	credentials, err := provider.Credentials(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials of wallet [%s]: %s", name, err)
+	}
+	return credentials, nil
+}
+
+// credentialsJSON returns the json of the given credentials or an empty string if nil
+func credentialsJSON(credentials *Credentials) (string, error) {
+	if credentials == nil {
+		return "", nil
+	}
+	return credentials.JSON()
+}
+
+func asInfos(walletsJSON string) ([]*Info, error) {
+	var infos []*Info
+	if err := json.Unmarshal([]byte(walletsJSON), &infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
 func registerType(typeName string, walletType Type) chan error {
 	logger.Debugf("Registering wallet type [%s]", typeName)
 
@@ -173,22 +533,87 @@ func registerType(typeName string, walletType Type) chan error {
 		errChan <- fmt.Errorf("wallet type name must be specified")
 		return errChan
 	}
+	if walletType == nil {
+		errChan <- fmt.Errorf("wallet type must be specified")
+		return errChan
+	}
+
+	cb := func(err error, data callback.Data) {
+		if err != nil {
+			logger.Debugf("Error registering wallet type [%s]: %s", typeName, err)
+		} else {
+			logger.Debugf("Successfully registered wallet type [%s]", typeName)
+		}
+		errChan <- err
+	}
 
-	// handle := callback.Register(func(err error, _ callback.Data) {
-	// 	if err != nil {
-	// 		logger.Debugf("Error registering wallet type [%s]: %s", typeName, err)
-	// 	} else {
-	// 		logger.Debugf("Successfully registered wallet type [%s]", typeName)
-	// 	}
-	// 	errChan <- err
-	// })
+	err := indy.RegisterWalletType(typeName, &typeAdapter{walletType: walletType}, cb)
+	if err != nil {
+		// Send the error immediately
+		errChan <- err
+	}
 
-	// if errCode := C.indy_register_wallet_type(
-	// 	(C.indy_handle_t)(handle), csPoolName, csName, csType, csConfig, csCredentials,
-	// 	(C.callback_fcn)(unsafe.Pointer(C.def_callback))); errCode != indyerror.Success {
-	// 	errChan <- indyerror.New(int32(errCode))
-	// }
+	return errChan
+}
 
-	// return errChan
-	panic("not implemented")
+// typeAdapter adapts a Type to the wallet type interface expected by libindy
+type typeAdapter struct {
+	walletType Type
+}
+
+func (a *typeAdapter) Create(name, config, credentials string) error {
+	return a.walletType.Create(name, config, credentials)
+}
+
+func (a *typeAdapter) Open(name, config, runtimeConfig, credentials string) (indy.WalletStorage, error) {
+	storage, err := a.walletType.Open(name, config, runtimeConfig, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := &storageAdapter{Storage: storage, name: name}
+	openStorage.Lock()
+	openStorage.storage[name] = adapter
+	openStorage.Unlock()
+
+	return adapter, nil
+}
+
+func (a *typeAdapter) Delete(name, config, credentials string) error {
+	return a.walletType.Delete(name, config, credentials)
+}
+
+// storageAdapter adapts a Storage to the wallet storage interface expected by libindy
+type storageAdapter struct {
+	Storage
+	name string
+}
+
+func (a *storageAdapter) Close() error {
+	openStorage.Lock()
+	if openStorage.storage[a.name] == a {
+		// The builtin delete is shadowed by the package's delete function
+		openStorage.storage[a.name] = nil
+	}
+	openStorage.Unlock()
+
+	return a.Storage.Close()
+}
+
+func (a *storageAdapter) List(keyPrefix string) (string, error) {
+	records, err := a.Storage.List(keyPrefix)
+	if err != nil {
+		return "", err
+	}
+	if records == nil {
+		records = []Record{}
+	}
+
+	bytes, err := json.Marshal(&struct {
+		Values []Record `json:"values"`
+	}{Values: records})
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
 }
@@ -7,7 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package wallet
 
 import (
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/hyperledger/indy-sdk-go/common/indyerror"
 )
 
 func TestWallet(t *testing.T) {
@@ -23,6 +27,14 @@ func TestWallet(t *testing.T) {
 		t.Log("Success received from Create")
 	}
 
+	exists, err := Exists(walletName)
+	if err != nil {
+		t.Fatalf("Error received from Exists: %s", err)
+	}
+	if !exists {
+		t.Fatalf("Expecting wallet [%s] to be listed", walletName)
+	}
+
 	w, err := Open(walletName, "", "")
 	if err != nil {
 		t.Fatalf("Error received from Open: %s", err)
@@ -46,16 +58,176 @@ func TestWallet(t *testing.T) {
 }
 
 type mockWalletType struct {
+	mutex   sync.Mutex
+	wallets map[string]map[string]string
+}
+
+func (m *mockWalletType) Create(name, config, credentials string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.wallets[name] = make(map[string]string)
+	return nil
+}
+
+func (m *mockWalletType) Open(name, config, runtimeConfig, credentials string) (Storage, error) {
+	return &mockStorage{walletType: m, name: name}, nil
+}
+
+func (m *mockWalletType) Delete(name, config, credentials string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// Note that the builtin delete is shadowed by the package's delete function
+	m.wallets[name] = nil
+	return nil
+}
+
+type mockStorage struct {
+	walletType *mockWalletType
+	name       string
+}
+
+func (s *mockStorage) Set(key, value string) error {
+	s.walletType.mutex.Lock()
+	defer s.walletType.mutex.Unlock()
+	s.walletType.wallets[s.name][key] = value
+	return nil
+}
+
+func (s *mockStorage) Get(key string) (string, error) {
+	s.walletType.mutex.Lock()
+	defer s.walletType.mutex.Unlock()
+	value, ok := s.walletType.wallets[s.name][key]
+	if !ok {
+		return "", indyerror.New(indyerror.WalletNotFoundError)
+	}
+	return value, nil
+}
+
+func (s *mockStorage) GetNotExpired(key string) (string, error) {
+	return s.Get(key)
+}
+
+func (s *mockStorage) List(keyPrefix string) ([]Record, error) {
+	s.walletType.mutex.Lock()
+	defer s.walletType.mutex.Unlock()
+	var records []Record
+	for key, value := range s.walletType.wallets[s.name] {
+		if strings.HasPrefix(key, keyPrefix) {
+			records = append(records, Record{Key: key, Value: value})
+		}
+	}
+	return records, nil
+}
+
+func (s *mockStorage) Close() error {
+	return nil
 }
 
 func TestRegisterWalletType(t *testing.T) {
-	t.SkipNow()
 	walletTypeName := "walletType1"
+	walletName := "mockwallet1"
 
-	walletType := &mockWalletType{}
+	walletType := &mockWalletType{wallets: make(map[string]map[string]string)}
 	err := RegisterType(walletTypeName, walletType)
 	if err != nil {
 		t.Fatalf("Error received from RegisterType: %s", err)
 	}
 	t.Log("Success received from RegisterType")
+
+	err = Create("poolx", walletName, walletTypeName, "", "")
+	if err != nil {
+		t.Fatalf("Error received from Create: %s", err)
+	}
+	if walletType.wallets[walletName] == nil {
+		t.Fatalf("Expecting wallet [%s] to be created by the wallet type", walletName)
+	}
+
+	w, err := Open(walletName, "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+
+	storage, err := w.Storage()
+	if err != nil {
+		t.Fatalf("Error received from Storage: %s", err)
+	}
+	if err := storage.Set("key1", "value1"); err != nil {
+		t.Fatalf("Error received from Set: %s", err)
+	}
+	if walletType.wallets[walletName]["key1"] != "value1" {
+		t.Fatalf("Expecting record to be stored by the wallet type")
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("Error received from Close: %s", err)
+	}
+	if _, err := w.Storage(); err == nil {
+		t.Fatalf("Expecting error getting storage of closed wallet")
+	}
+
+	err = Delete(walletName, "")
+	if err != nil {
+		t.Fatalf("Error received from Delete: %s", err)
+	}
+	if walletType.wallets[walletName] != nil {
+		t.Fatalf("Expecting wallet [%s] to be deleted by the wallet type", walletName)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	walletType := &mockWalletType{wallets: make(map[string]map[string]string)}
+	adapter := &typeAdapter{walletType: walletType}
+	adapter.Create("wallet1", "", "")
+
+	w := &Wallet{Name: "wallet1", handle: 1}
+	if _, err := w.Storage(); err == nil {
+		t.Fatalf("Expecting error getting storage of wallet that isn't open")
+	}
+
+	storage, err := adapter.Open("wallet1", "", "", "")
+	if err != nil {
+		t.Fatalf("Error received from Open: %s", err)
+	}
+	s, err := w.Storage()
+	if err != nil {
+		t.Fatalf("Error received from Storage: %s", err)
+	}
+	if _, ok := s.(*mockStorage); !ok {
+		t.Fatalf("Expecting storage of the wallet type but got %T", s)
+	}
+
+	storage.Close()
+	if _, err := w.Storage(); err == nil {
+		t.Fatalf("Expecting error getting storage after the wallet type closed it")
+	}
+}
+
+func TestAsInfos(t *testing.T) {
+	infos, err := asInfos(`[{"name":"wallet1","associated_pool_name":"pool1","type":"default"},{"name":"wallet2","associated_pool_name":"pool2","type":"kv"}]`)
+	if err != nil {
+		t.Fatalf("Error received from asInfos: %s", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Expecting 2 wallets but got %d", len(infos))
+	}
+	if *infos[1] != (Info{Name: "wallet2", PoolName: "pool2", Type: "kv"}) {
+		t.Fatalf("Unexpected wallet info: %+v", infos[1])
+	}
+}
+
+func TestClosedWallet(t *testing.T) {
+	w := &Wallet{Name: "wallet1", handle: 1, closed: true}
+
+	if _, err := w.OpenHandle(); err != ErrClosed {
+		t.Fatalf("Expecting error [%s] but got [%v]", ErrClosed, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Expecting Close of closed wallet to succeed but got [%v]", err)
+	}
+
+	var nilWallet *Wallet
+	if _, err := nilWallet.OpenHandle(); err == nil {
+		t.Fatalf("Expecting error for nil wallet")
+	}
 }